@@ -0,0 +1,79 @@
+package plex
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ActivityOptions limit User.GetAllActivity. The zero value queries 4 servers at a time and gives each 10 seconds
+// to answer.
+type ActivityOptions struct {
+	// Concurrency is how many servers are queried at a time
+	Concurrency int
+	// Timeout is how long each server has to answer
+	Timeout time.Duration
+}
+
+// ServerActivity is the activity on a single server, or the error that prevented getting it
+type ServerActivity struct {
+	Server Server
+	Videos []Video
+	Err    error
+}
+
+// GetAllActivity gets the activity on all of the user's servers at once, so a slow or unreachable server does not
+// hold up the others, within the limits in opts. The results are in the same order as GetServers, a server that
+// failed has its Err set.
+func (user User) GetAllActivity(ctx context.Context, opts ActivityOptions) ([]ServerActivity, error) {
+	servers, err := user.GetServers()
+	if err != nil {
+		return nil, err
+	}
+
+	return getAllActivity(ctx, servers, opts), nil
+}
+
+func getAllActivity(ctx context.Context, servers []Server, opts ActivityOptions) []ServerActivity {
+	results := make([]ServerActivity, len(servers))
+	limit := make(chan struct{}, opts.concurrency())
+	timeout := opts.timeout()
+
+	var wg sync.WaitGroup
+	for i, server := range servers {
+		wg.Add(1)
+		go func(i int, server Server) {
+			defer wg.Done()
+
+			results[i].Server = server
+			select {
+			case limit <- struct{}{}:
+				defer func() { <-limit }()
+			case <-ctx.Done():
+				results[i].Err = ctx.Err()
+				return
+			}
+
+			serverCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			results[i].Videos, results[i].Err = server.GetActivityContext(serverCtx)
+		}(i, server)
+	}
+	wg.Wait()
+
+	return results
+}
+
+func (opts ActivityOptions) concurrency() int {
+	if opts.Concurrency <= 0 {
+		return 4
+	}
+	return opts.Concurrency
+}
+
+func (opts ActivityOptions) timeout() time.Duration {
+	if opts.Timeout <= 0 {
+		return 10 * time.Second
+	}
+	return opts.Timeout
+}
@@ -0,0 +1,104 @@
+package plex
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func startActivityServer(t *testing.T, handler http.HandlerFunc) (*httptest.Server, Server) {
+	testServer, server := startTestServer(t, handler)
+	server.Name = testServer.URL
+	return testServer, server
+}
+
+func TestGetAllActivity(t *testing.T) {
+	fast, fastServer := startActivityServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<MediaContainer size="1"><Video ratingKey="1" title="Fast" /></MediaContainer>`))
+	})
+	defer fast.Close()
+
+	slow, slowServer := startActivityServer(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	})
+	defer slow.Close()
+
+	unauthorized, unauthorizedServer := startActivityServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	defer unauthorized.Close()
+
+	started := time.Now()
+	results := getAllActivity(context.Background(), []Server{slowServer, fastServer, unauthorizedServer},
+		ActivityOptions{Timeout: 100 * time.Millisecond})
+	if elapsed := time.Since(started); elapsed > 2*time.Second {
+		t.Fatalf("Slow server was not timed out, took %s", elapsed)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %+v", results)
+	}
+	if results[0].Server.Name != slowServer.Name || results[0].Err == nil {
+		t.Errorf("Expected the slow server to time out, got %+v", results[0])
+	}
+	if results[1].Server.Name != fastServer.Name || results[1].Err != nil ||
+		len(results[1].Videos) != 1 || results[1].Videos[0].Title != "Fast" {
+		t.Errorf("Expected the fast server's activity, got %+v", results[1])
+	}
+	if results[2].Server.Name != unauthorizedServer.Name || results[2].Err == nil {
+		t.Errorf("Expected the unauthorized server to fail, got %+v", results[2])
+	}
+}
+
+func TestGetAllActivityConcurrency(t *testing.T) {
+	var inFlight, maxInFlight int32
+	testServer, server := startActivityServer(t, func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+				break
+			}
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte(`<MediaContainer size="0"></MediaContainer>`))
+	})
+	defer testServer.Close()
+
+	results := getAllActivity(context.Background(), []Server{server, server, server, server, server},
+		ActivityOptions{Concurrency: 2})
+	for _, result := range results {
+		if result.Err != nil {
+			t.Fatal(result.Err)
+		}
+	}
+	if maxInFlight > 2 {
+		t.Fatalf("Expected at most 2 concurrent requests, got %d", maxInFlight)
+	}
+}
+
+func TestGetAllActivityFail(t *testing.T) {
+	user := User{
+		AuthToken: "authToken",
+	}
+
+	expectedReq, err := http.NewRequest("GET", "https://plex.tv/devices.xml", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedReq.Header.Add("X-Plex-Client-Identifier", "plextrack")
+	expectedReq.Header.Add("X-Plex-Token", "authToken")
+	client = makeFakeClient(t, http.StatusUnauthorized, "", expectedReq)
+
+	if _, err := user.GetAllActivity(context.Background(), ActivityOptions{}); err == nil {
+		t.Fatal("Should err when the servers can't be listed")
+	}
+}
@@ -0,0 +1,66 @@
+package plex
+
+import (
+	"encoding/xml"
+	"net"
+	"net/url"
+	"strconv"
+)
+
+type clientsResp struct {
+	XMLName xml.Name `xml:"MediaContainer" json:"-"`
+	Clients []Client `xml:"Server" json:"Server"`
+}
+
+// Client is a player the server knows about, whether or not it is currently playing anything
+type Client struct {
+	Name                 string              `xml:"name,attr" json:"name"`
+	Host                 string              `xml:"host,attr" json:"host"`
+	Address              string              `xml:"address,attr" json:"address"`
+	Port                 int                 `xml:"port,attr" json:"port"`
+	MachineIdentifier    string              `xml:"machineIdentifier,attr" json:"machineIdentifier"`
+	Version              string              `xml:"version,attr" json:"version"`
+	Product              string              `xml:"product,attr" json:"product"`
+	Platform             string              `xml:"platform,attr" json:"platform"`
+	DeviceClass          string              `xml:"deviceClass,attr" json:"deviceClass"`
+	Protocol             string              `xml:"protocol,attr" json:"protocol"`
+	ProtocolVersion      string              `xml:"protocolVersion,attr" json:"protocolVersion"`
+	ProtocolCapabilities CommaSeperatedSlice `xml:"protocolCapabilities,attr" json:"protocolCapabilities"`
+}
+
+func (server Server) GetClients() ([]Client, error) {
+	container := &clientsResp{}
+	if err := server.fetch("GET", "/clients", nil, container); err != nil {
+		return nil, err
+	}
+
+	return container.Clients, nil
+}
+
+func (c Client) HasCapability(capability string) bool {
+	for _, provided := range c.ProtocolCapabilities {
+		if provided == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// Player returns the client as a Player so it can be passed to Server.Control
+func (c Client) Player() Player {
+	return Player{
+		MachineIdentifier: c.MachineIdentifier,
+		Platform:          c.Platform,
+		Product:           c.Product,
+		Title:             c.Name,
+	}
+}
+
+// URL is the address the client can be reached at directly, for use with Server.ControlDirect
+func (c Client) URL() HTTPURL {
+	host := c.Address
+	if host == "" {
+		host = c.Host
+	}
+	return HTTPURL{url.URL{Scheme: "http", Host: net.JoinHostPort(host, strconv.Itoa(c.Port))}}
+}
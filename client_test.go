@@ -0,0 +1,103 @@
+package plex
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestGetClientsSuccess(t *testing.T) {
+	resp := `<?xml version="1.0" encoding="UTF-8"?>
+	<MediaContainer size="2">
+	  <Server name="Living Room" host="192.168.1.5" address="192.168.1.5" port="32500" machineIdentifier="roku-1" version="6.0" protocol="plex" product="Plex for Roku" platform="Roku" deviceClass="stb" protocolVersion="1" protocolCapabilities="timeline,playback,navigation,playqueues" />
+	  <Server name="Bedroom" host="bedroom.local" port="32433" machineIdentifier="web-1" product="Plex Web" protocolCapabilities="playback" />
+	</MediaContainer>`
+
+	client = makeFakeClient(t, http.StatusOK, resp, makeServerRequest(t, "GET", "http://server.com:4040/clients"))
+
+	result, err := makeTestServer().GetClients()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Client{
+		Client{
+			Name:                 "Living Room",
+			Host:                 "192.168.1.5",
+			Address:              "192.168.1.5",
+			Port:                 32500,
+			MachineIdentifier:    "roku-1",
+			Version:              "6.0",
+			Product:              "Plex for Roku",
+			Platform:             "Roku",
+			DeviceClass:          "stb",
+			Protocol:             "plex",
+			ProtocolVersion:      "1",
+			ProtocolCapabilities: []string{"timeline", "playback", "navigation", "playqueues"},
+		},
+		Client{
+			Name:                 "Bedroom",
+			Host:                 "bedroom.local",
+			Port:                 32433,
+			MachineIdentifier:    "web-1",
+			Product:              "Plex Web",
+			ProtocolCapabilities: []string{"playback"},
+		},
+	}
+
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("\nExpected: %+v\n\nGot: %+v", expected, result)
+	}
+
+	if !result[0].HasCapability("playqueues") || result[1].HasCapability("playqueues") {
+		t.Fatal("HasCapability did not match the advertised capabilities")
+	}
+
+	expectedURL := HTTPURL{url.URL{Scheme: "http", Host: "bedroom.local:32433"}}
+	if result[1].URL() != expectedURL {
+		t.Fatalf("\nExpected: %+v\nGot: %+v", expectedURL, result[1].URL())
+	}
+}
+
+func TestGetClientsJSONSuccess(t *testing.T) {
+	resp := `{"MediaContainer": {"size": 1, "Server": [
+	  {"name": "Living Room", "host": "192.168.1.5", "address": "192.168.1.5", "port": 32500, "machineIdentifier": "roku-1", "version": "6.0", "protocol": "plex", "product": "Plex for Roku", "platform": "Roku", "deviceClass": "stb", "protocolVersion": "1", "protocolCapabilities": "timeline,playback"}
+	]}}`
+
+	client = makeFakeClient(t, http.StatusOK, resp, makeJSONServerRequest(t, "GET", "http://server.com:4040/clients"))
+
+	result, err := makeJSONTestServer().GetClients()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Client{
+		Client{
+			Name:                 "Living Room",
+			Host:                 "192.168.1.5",
+			Address:              "192.168.1.5",
+			Port:                 32500,
+			MachineIdentifier:    "roku-1",
+			Version:              "6.0",
+			Product:              "Plex for Roku",
+			Platform:             "Roku",
+			DeviceClass:          "stb",
+			Protocol:             "plex",
+			ProtocolVersion:      "1",
+			ProtocolCapabilities: []string{"timeline", "playback"},
+		},
+	}
+
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("\nExpected: %+v\n\nGot: %+v", expected, result)
+	}
+}
+
+func TestGetClientsFail(t *testing.T) {
+	client = makeFakeClient(t, http.StatusUnauthorized, "", makeServerRequest(t, "GET", "http://server.com:4040/clients"))
+
+	if _, err := makeTestServer().GetClients(); err == nil {
+		t.Fatal("GetClients returned success when it received bad status code")
+	}
+}
@@ -0,0 +1,216 @@
+package plex
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type collectionsResp struct {
+	XMLName     xml.Name     `xml:"MediaContainer" json:"-"`
+	Collections []Collection `xml:"Directory" json:"Metadata"`
+}
+
+type itemsResp struct {
+	XMLName xml.Name `xml:"MediaContainer" json:"-"`
+	Items   []Video  `xml:",any" json:"Metadata"`
+}
+
+type CollectionMode int
+
+const (
+	CollectionModeDefault   CollectionMode = -1
+	CollectionModeHide      CollectionMode = 0
+	CollectionModeHideItems CollectionMode = 1
+	CollectionModeShowItems CollectionMode = 2
+)
+
+type CollectionSort int
+
+const (
+	CollectionSortRelease CollectionSort = 0
+	CollectionSortAlpha   CollectionSort = 1
+	CollectionSortCustom  CollectionSort = 2
+)
+
+type Collection struct {
+	RatingKey        string         `xml:"ratingKey,attr" json:"ratingKey"`
+	Key              string         `xml:"key,attr" json:"key"`
+	GUID             string         `xml:"guid,attr" json:"guid"`
+	Title            string         `xml:"title,attr" json:"title"`
+	Subtype          string         `xml:"subtype,attr" json:"subtype"`
+	Summary          string         `xml:"summary,attr" json:"summary"`
+	Smart            IntAsBool      `xml:"smart,attr" json:"smart"`
+	ChildCount       int            `xml:"childCount,attr" json:"childCount"`
+	CollectionMode   CollectionMode `xml:"collectionMode,attr" json:"collectionMode"`
+	CollectionSort   CollectionSort `xml:"collectionSort,attr" json:"collectionSort"`
+	LibrarySectionID ID             `xml:"librarySectionID,attr" json:"librarySectionID"`
+	Thumb            URLPath        `xml:"thumb,attr" json:"thumb"`
+	Art              URLPath        `xml:"art,attr" json:"art"`
+	AddedAt          UnixTime       `xml:"addedAt,attr" json:"addedAt"`
+	UpdatedAt        UnixTime       `xml:"updatedAt,attr" json:"updatedAt"`
+	Server           Server         `xml:"-" json:"-"`
+}
+
+func (section Section) GetCollections() ([]Collection, error) {
+	container := &collectionsResp{}
+	if err := section.Server.fetch("GET", "/library/sections/"+section.Key+"/collections", nil, container); err != nil {
+		return nil, err
+	}
+
+	for i := range container.Collections {
+		container.Collections[i].Server = section.Server
+	}
+
+	return container.Collections, nil
+}
+
+// CreateCollection creates a regular collection in the section containing the items with the given rating keys
+func (section Section) CreateCollection(title string, ratingKeys ...string) (Collection, error) {
+	if len(ratingKeys) == 0 {
+		return Collection{}, errors.New("A collection must be created with at least one item")
+	}
+
+	uri := section.Server.libraryURI("/library/metadata/" + strings.Join(ratingKeys, ","))
+	return section.createCollection(title, false, uri)
+}
+
+// CreateSmartCollection creates a collection whose items are every item in the section matching filter.
+// Filter takes the same parameters as the section's /all endpoint, e.g. genre=1234 or year>>=2020
+func (section Section) CreateSmartCollection(title string, filter url.Values) (Collection, error) {
+	uri, err := section.smartURI(filter)
+	if err != nil {
+		return Collection{}, err
+	}
+	return section.createCollection(title, true, uri)
+}
+
+func (section Section) createCollection(title string, smart bool, uri string) (Collection, error) {
+	metadataType, err := lookupMetadataType(section.Type)
+	if err != nil {
+		return Collection{}, err
+	}
+
+	params := url.Values{}
+	params.Set("type", strconv.Itoa(metadataType))
+	params.Set("title", title)
+	params.Set("smart", boolParam(smart))
+	params.Set("sectionId", section.Key)
+	params.Set("uri", uri)
+
+	container := &collectionsResp{}
+	if err := section.Server.fetch("POST", "/library/collections", params, container); err != nil {
+		return Collection{}, err
+	}
+
+	if len(container.Collections) == 0 {
+		return Collection{}, errors.New("Server did not return the created collection")
+	}
+
+	collection := container.Collections[0]
+	collection.Server = section.Server
+	return collection, nil
+}
+
+func (section Section) smartURI(filter url.Values) (string, error) {
+	metadataType, err := lookupMetadataType(section.Type)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	for key, values := range filter {
+		query[key] = values
+	}
+	query.Set("type", strconv.Itoa(metadataType))
+
+	return section.Server.libraryURI("/library/sections/" + section.Key + "/all?" + query.Encode()), nil
+}
+
+func (collection Collection) GetItems() ([]Video, error) {
+	container := &itemsResp{}
+	if err := collection.Server.fetch("GET", "/library/collections/"+collection.RatingKey+"/children", nil, container); err != nil {
+		return nil, err
+	}
+
+	for i := range container.Items {
+		container.Items[i].Server = collection.Server
+	}
+
+	return container.Items, nil
+}
+
+func (collection Collection) AddItems(ratingKeys ...string) error {
+	if collection.Smart {
+		return errors.New("Items cannot be added to a smart collection, update its filter instead")
+	}
+	if len(ratingKeys) == 0 {
+		return errors.New("No items to add to the collection")
+	}
+
+	params := url.Values{}
+	params.Set("uri", collection.Server.libraryURI("/library/metadata/"+strings.Join(ratingKeys, ",")))
+
+	return collection.Server.fetch("PUT", "/library/collections/"+collection.RatingKey+"/items", params, nil)
+}
+
+func (collection Collection) RemoveItem(ratingKey string) error {
+	if collection.Smart {
+		return errors.New("Items cannot be removed from a smart collection, update its filter instead")
+	}
+
+	return collection.Server.fetch("DELETE", "/library/collections/"+collection.RatingKey+"/items/"+ratingKey, nil, nil)
+}
+
+// UpdateFilter replaces the filter of a smart collection
+func (collection Collection) UpdateFilter(filter url.Values) error {
+	if !collection.Smart {
+		return errors.New("Only smart collections have a filter")
+	}
+	if collection.LibrarySectionID == "" {
+		return errors.New("Collection has no library section")
+	}
+
+	section := Section{
+		Key:    string(collection.LibrarySectionID),
+		Type:   collection.Subtype,
+		Server: collection.Server,
+	}
+
+	uri, err := section.smartURI(filter)
+	if err != nil {
+		return err
+	}
+
+	params := url.Values{}
+	params.Set("uri", uri)
+
+	return collection.Server.fetch("PUT", "/library/collections/"+collection.RatingKey+"/items", params, nil)
+}
+
+func (collection Collection) SetMode(mode CollectionMode) error {
+	params := url.Values{}
+	params.Set("collectionMode", strconv.Itoa(int(mode)))
+
+	return collection.Server.fetch("PUT", "/library/metadata/"+collection.RatingKey+"/prefs", params, nil)
+}
+
+func (collection Collection) SetSort(sort CollectionSort) error {
+	params := url.Values{}
+	params.Set("collectionSort", strconv.Itoa(int(sort)))
+
+	return collection.Server.fetch("PUT", "/library/metadata/"+collection.RatingKey+"/prefs", params, nil)
+}
+
+func (collection Collection) Delete() error {
+	return collection.Server.fetch("DELETE", "/library/collections/"+collection.RatingKey, nil, nil)
+}
+
+func boolParam(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
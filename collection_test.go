@@ -0,0 +1,277 @@
+package plex
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func makeTestSection() Section {
+	return Section{Key: "1", Type: "movie", Server: makeTestServer()}
+}
+
+func TestGetCollectionsSuccess(t *testing.T) {
+	resp := `<?xml version="1.0" encoding="UTF-8"?>
+	<MediaContainer size="2">
+	  <Directory ratingKey="2001" key="/library/collections/2001/children" guid="collection://abc" type="collection" title="Oscars 2026" subtype="movie" summary="" smart="0" childCount="3" collectionMode="2" collectionSort="1" librarySectionID="1" thumb="/library/collections/2001/composite/1" addedAt="1430373171" updatedAt="1430373196" />
+	  <Directory ratingKey="2002" key="/library/collections/2002/children" guid="collection://def" type="collection" title="Eighties" subtype="movie" smart="1" childCount="42" librarySectionID="1" addedAt="1430373171" updatedAt="1430373196" />
+	</MediaContainer>`
+
+	client = makeFakeClient(t, http.StatusOK, resp, makeServerRequest(t, "GET", "http://server.com:4040/library/sections/1/collections"))
+
+	result, err := makeTestSection().GetCollections()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Collection{
+		Collection{
+			RatingKey:        "2001",
+			Key:              "/library/collections/2001/children",
+			GUID:             "collection://abc",
+			Title:            "Oscars 2026",
+			Subtype:          "movie",
+			ChildCount:       3,
+			CollectionMode:   CollectionModeShowItems,
+			CollectionSort:   CollectionSortAlpha,
+			LibrarySectionID: "1",
+			Thumb:            URLPath{url.URL{Path: "/library/collections/2001/composite/1"}},
+			AddedAt:          UnixTime{time.Unix(1430373171, 0)},
+			UpdatedAt:        UnixTime{time.Unix(1430373196, 0)},
+			Server:           makeTestServer(),
+		},
+		Collection{
+			RatingKey:        "2002",
+			Key:              "/library/collections/2002/children",
+			GUID:             "collection://def",
+			Title:            "Eighties",
+			Subtype:          "movie",
+			Smart:            true,
+			ChildCount:       42,
+			LibrarySectionID: "1",
+			AddedAt:          UnixTime{time.Unix(1430373171, 0)},
+			UpdatedAt:        UnixTime{time.Unix(1430373196, 0)},
+			Server:           makeTestServer(),
+		},
+	}
+
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("\nExpected: %+v\n\nGot: %+v", expected, result)
+	}
+}
+
+func TestGetCollectionsJSONSuccess(t *testing.T) {
+	resp := `{"MediaContainer": {"size": 1, "Metadata": [
+	  {"ratingKey": "2001", "key": "/library/collections/2001/children", "guid": "collection://abc", "type": "collection", "title": "Oscars 2026", "subtype": "movie", "smart": "1", "childCount": 3, "collectionMode": 2, "collectionSort": 1, "librarySectionID": 1, "thumb": "/library/collections/2001/composite/1", "addedAt": 1430373171, "updatedAt": 1430373196}
+	]}}`
+
+	expectedReq := makeJSONServerRequest(t, "GET", "http://server.com:4040/library/sections/1/collections")
+	client = makeFakeClient(t, http.StatusOK, resp, expectedReq)
+
+	section := Section{Key: "1", Type: "movie", Server: makeJSONTestServer()}
+	result, err := section.GetCollections()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Collection{
+		Collection{
+			RatingKey:        "2001",
+			Key:              "/library/collections/2001/children",
+			GUID:             "collection://abc",
+			Title:            "Oscars 2026",
+			Subtype:          "movie",
+			Smart:            true,
+			ChildCount:       3,
+			CollectionMode:   CollectionModeShowItems,
+			CollectionSort:   CollectionSortAlpha,
+			LibrarySectionID: "1",
+			Thumb:            URLPath{url.URL{Path: "/library/collections/2001/composite/1"}},
+			AddedAt:          UnixTime{time.Unix(1430373171, 0)},
+			UpdatedAt:        UnixTime{time.Unix(1430373196, 0)},
+			Server:           makeJSONTestServer(),
+		},
+	}
+
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("\nExpected: %+v\n\nGot: %+v", expected, result)
+	}
+}
+
+func TestCreateCollectionSuccess(t *testing.T) {
+	resp := `<?xml version="1.0" encoding="UTF-8"?>
+	<MediaContainer size="1">
+	  <Directory ratingKey="2001" key="/library/collections/2001/children" type="collection" title="Oscars 2026" subtype="movie" smart="0" childCount="2" librarySectionID="1" />
+	</MediaContainer>`
+
+	expectedReq := makeServerRequest(t, "POST", "http://server.com:4040/library/collections?"+
+		"sectionId=1&smart=0&title=Oscars+2026&type=1&"+
+		"uri=server%3A%2F%2FmachineID%2Fcom.plexapp.plugins.library%2Flibrary%2Fmetadata%2F10%2C11")
+	client = makeFakeClient(t, http.StatusOK, resp, expectedReq)
+
+	result, err := makeTestSection().CreateCollection("Oscars 2026", "10", "11")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Collection{
+		RatingKey:        "2001",
+		Key:              "/library/collections/2001/children",
+		Title:            "Oscars 2026",
+		Subtype:          "movie",
+		ChildCount:       2,
+		LibrarySectionID: "1",
+		Server:           makeTestServer(),
+	}
+
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("\nExpected: %+v\n\nGot: %+v", expected, result)
+	}
+}
+
+func TestCreateCollectionWithoutItems(t *testing.T) {
+	if _, err := makeTestSection().CreateCollection("Empty"); err == nil {
+		t.Fatal("Should err when no items are given")
+	}
+}
+
+func TestCreateSmartCollectionSuccess(t *testing.T) {
+	resp := `<?xml version="1.0" encoding="UTF-8"?>
+	<MediaContainer size="1">
+	  <Directory ratingKey="2002" type="collection" title="Comedies" subtype="movie" smart="1" librarySectionID="1" />
+	</MediaContainer>`
+
+	expectedReq := makeServerRequest(t, "POST", "http://server.com:4040/library/collections?"+
+		"sectionId=1&smart=1&title=Comedies&type=1&"+
+		"uri=server%3A%2F%2FmachineID%2Fcom.plexapp.plugins.library%2Flibrary%2Fsections%2F1%2Fall%3Fgenre%3D12%26type%3D1")
+	client = makeFakeClient(t, http.StatusOK, resp, expectedReq)
+
+	result, err := makeTestSection().CreateSmartCollection("Comedies", url.Values{"genre": {"12"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !result.Smart || result.RatingKey != "2002" {
+		t.Fatalf("Unexpected collection %+v", result)
+	}
+}
+
+func TestCreateCollectionFail(t *testing.T) {
+	section := makeTestSection()
+	section.Type = "unknown"
+
+	if _, err := section.CreateCollection("Oscars 2026", "10"); err == nil {
+		t.Fatal("Should err when the section type is unknown")
+	}
+}
+
+func TestGetCollectionItemsSuccess(t *testing.T) {
+	resp := `<?xml version="1.0" encoding="UTF-8"?>
+	<MediaContainer size="2">
+	  <Video ratingKey="10" key="/library/metadata/10" type="movie" title="First" />
+	  <Directory ratingKey="11" key="/library/metadata/11/children" type="show" title="Second" />
+	</MediaContainer>`
+
+	client = makeFakeClient(t, http.StatusOK, resp, makeServerRequest(t, "GET", "http://server.com:4040/library/collections/2001/children"))
+
+	server := makeTestServer()
+	collection := Collection{RatingKey: "2001", Server: server}
+	result, err := collection.GetItems()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Video{
+		Video{RatingKey: "10", Key: "/library/metadata/10", Type: "movie", Title: "First", Server: server},
+		Video{RatingKey: "11", Key: "/library/metadata/11/children", Type: "show", Title: "Second", Server: server},
+	}
+
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("\nExpected: %+v\n\nGot: %+v", expected, result)
+	}
+}
+
+func TestAddCollectionItemsSuccess(t *testing.T) {
+	expectedReq := makeServerRequest(t, "PUT", "http://server.com:4040/library/collections/2001/items?"+
+		"uri=server%3A%2F%2FmachineID%2Fcom.plexapp.plugins.library%2Flibrary%2Fmetadata%2F12")
+	client = makeFakeClient(t, http.StatusOK, "", expectedReq)
+
+	collection := Collection{RatingKey: "2001", Server: makeTestServer()}
+	if err := collection.AddItems("12"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAddSmartCollectionItemsFail(t *testing.T) {
+	collection := Collection{RatingKey: "2002", Smart: true, Server: makeTestServer()}
+	if err := collection.AddItems("12"); err == nil {
+		t.Fatal("Should err when adding items to a smart collection")
+	}
+}
+
+func TestAddNoCollectionItemsFail(t *testing.T) {
+	collection := Collection{RatingKey: "2001", Server: makeTestServer()}
+	if err := collection.AddItems(); err == nil {
+		t.Fatal("Should err when adding no items")
+	}
+}
+
+func TestRemoveCollectionItemSuccess(t *testing.T) {
+	expectedReq := makeServerRequest(t, "DELETE", "http://server.com:4040/library/collections/2001/items/12")
+	client = makeFakeClient(t, http.StatusOK, "", expectedReq)
+
+	collection := Collection{RatingKey: "2001", Server: makeTestServer()}
+	if err := collection.RemoveItem("12"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUpdateSmartCollectionFilterSuccess(t *testing.T) {
+	expectedReq := makeServerRequest(t, "PUT", "http://server.com:4040/library/collections/2002/items?"+
+		"uri=server%3A%2F%2FmachineID%2Fcom.plexapp.plugins.library%2Flibrary%2Fsections%2F1%2Fall%3Fgenre%3D13%26type%3D1")
+	client = makeFakeClient(t, http.StatusOK, "", expectedReq)
+
+	collection := Collection{RatingKey: "2002", Smart: true, Subtype: "movie", LibrarySectionID: "1", Server: makeTestServer()}
+	if err := collection.UpdateFilter(url.Values{"genre": {"13"}}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUpdateFilterWithoutSectionFail(t *testing.T) {
+	collection := Collection{RatingKey: "2002", Smart: true, Subtype: "movie", Server: makeTestServer()}
+	if err := collection.UpdateFilter(url.Values{"genre": {"13"}}); err == nil {
+		t.Fatal("Should err when the collection's library section is unknown")
+	}
+}
+
+func TestSetCollectionModeSuccess(t *testing.T) {
+	expectedReq := makeServerRequest(t, "PUT", "http://server.com:4040/library/metadata/2001/prefs?collectionMode=0")
+	client = makeFakeClient(t, http.StatusOK, "", expectedReq)
+
+	collection := Collection{RatingKey: "2001", Server: makeTestServer()}
+	if err := collection.SetMode(CollectionModeHide); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSetCollectionSortSuccess(t *testing.T) {
+	expectedReq := makeServerRequest(t, "PUT", "http://server.com:4040/library/metadata/2001/prefs?collectionSort=2")
+	client = makeFakeClient(t, http.StatusOK, "", expectedReq)
+
+	collection := Collection{RatingKey: "2001", Server: makeTestServer()}
+	if err := collection.SetSort(CollectionSortCustom); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeleteCollectionFail(t *testing.T) {
+	expectedReq := makeServerRequest(t, "DELETE", "http://server.com:4040/library/collections/2001")
+	client = makeFakeClient(t, http.StatusNotFound, "", expectedReq)
+
+	collection := Collection{RatingKey: "2001", Server: makeTestServer()}
+	if err := collection.Delete(); err == nil {
+		t.Fatal("Delete returned success when it received bad status code")
+	}
+}
@@ -7,20 +7,64 @@ import (
 )
 
 type Device struct {
-	Name          string              `xml:"name,attr"`
-	PublicAddress HTTPURL             `xml:"publicAddress,attr"`
-	Product       string              `xml:"product,attr"`
-	Provides      CommaSeperatedSlice `xml:"provides,attr"`
-	Connections   []Connection        `xml:"Connection"`
-	Owner         User
+	Name             string              `xml:"name,attr" json:"name"`
+	ClientIdentifier string              `xml:"clientIdentifier,attr" json:"clientIdentifier"`
+	PublicAddress    HTTPURL             `xml:"publicAddress,attr" json:"publicAddress"`
+	Product          string              `xml:"product,attr" json:"product"`
+	Provides         CommaSeperatedSlice `xml:"provides,attr" json:"provides"`
+	Connections      []Connection        `xml:"Connection" json:"Connection"`
+	Owner            User
 }
 
 type Connection struct {
-	Address HTTPURL `xml:"uri,attr"`
+	Address HTTPURL `xml:"uri,attr" json:"uri"`
 }
 
 type Server struct {
 	Device
+	Format WireFormat
+	// Retry is the policy for requests to the server
+	Retry RetryPolicy
+}
+
+type DeviceKind int
+
+const (
+	DeviceKindUnknown DeviceKind = iota
+	DeviceKindServer
+	DeviceKindPlayer
+	DeviceKindController
+	DeviceKindSyncTarget
+)
+
+func (kind DeviceKind) String() string {
+	switch kind {
+	case DeviceKindServer:
+		return "server"
+	case DeviceKindPlayer:
+		return "player"
+	case DeviceKindController:
+		return "controller"
+	case DeviceKindSyncTarget:
+		return "sync target"
+	}
+	return "unknown"
+}
+
+// Kind classifies the device by the most significant feature it provides. A server that can also play is a
+// server, and a phone that can both play and control other players is a player.
+func (device *Device) Kind() DeviceKind {
+	switch {
+	case device.ProvidesFeature("server"):
+		return DeviceKindServer
+	case device.ProvidesFeature("player"), device.ProvidesFeature("client"):
+		return DeviceKindPlayer
+	case device.ProvidesFeature("controller"):
+		return DeviceKindController
+	case device.ProvidesFeature("sync-target"):
+		return DeviceKindSyncTarget
+	}
+	return DeviceKindUnknown
 }
 
 func (device *Device) ProvidesFeature(feature string) bool {
@@ -37,7 +81,7 @@ func (device Device) toServer() (Server, error) {
 		return Server{}, fmt.Errorf("Device %s is not a server", device.Name)
 	}
 
-	server := Server{device}
+	server := Server{Device: device, Retry: device.Owner.Retry}
 	// The public address for a server is missing the port, but the connection that matches has it
 	fullPublicAddr, err := findMatchingConnection(server.PublicAddress.URL, server.Connections)
 	if err == nil {
@@ -52,5 +96,5 @@ func findMatchingConnection(address url.URL, connections []Connection) (HTTPURL,
 			return connection.Address, nil
 		}
 	}
-	return HTTPURL{}, fmt.Errorf("No matching connection found for %s in %v", address, connections)
+	return HTTPURL{}, fmt.Errorf("No matching connection found for %s in %v", address.String(), connections)
 }
@@ -0,0 +1,26 @@
+package plex
+
+import "testing"
+
+func TestDeviceKind(t *testing.T) {
+	tests := []struct {
+		provides CommaSeperatedSlice
+		expected DeviceKind
+	}{
+		{CommaSeperatedSlice{"server"}, DeviceKindServer},
+		{CommaSeperatedSlice{"player", "server"}, DeviceKindServer},
+		{CommaSeperatedSlice{"client", "controller", "sync-target", "player", "pubsub-player"}, DeviceKindPlayer},
+		{CommaSeperatedSlice{"client"}, DeviceKindPlayer},
+		{CommaSeperatedSlice{"controller", "sync-target"}, DeviceKindController},
+		{CommaSeperatedSlice{"sync-target"}, DeviceKindSyncTarget},
+		{CommaSeperatedSlice{""}, DeviceKindUnknown},
+		{nil, DeviceKindUnknown},
+	}
+
+	for _, test := range tests {
+		device := Device{Provides: test.provides}
+		if kind := device.Kind(); kind != test.expected {
+			t.Errorf("Provides %v: expected %s, got %s", test.provides, test.expected, kind)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package plex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// DownloadOptions control which bytes of a part DownloadPart fetches
+type DownloadOptions struct {
+	// Offset is the first byte to download, e.g. the size of a partial file to resume
+	Offset int64
+	// Length is the number of bytes to download. Zero downloads to the end of the part.
+	Length int64
+	// Progress is called after each write with the number of bytes of the part written so far, including Offset,
+	// and the part's size
+	Progress func(written, size int64)
+}
+
+// PartURL returns a URL the original file of part can be streamed from directly. It includes the owner's token
+// so it can be handed to a player or download manager.
+func (server Server) PartURL(part Part) HTTPURL {
+	return server.ResolveWithToken(part.Key)
+}
+
+// DownloadPart writes the original file of part to w. When the part's size is known the number of bytes written is
+// checked against it, so a truncated download returns an error.
+func (server Server) DownloadPart(ctx context.Context, part Part, w io.Writer, opts DownloadOptions) error {
+	if part.Key == (URLPath{}) {
+		return errors.New("Part has no key")
+	}
+	if opts.Offset < 0 || opts.Length < 0 {
+		return errors.New("Download offset and length can't be negative")
+	}
+
+	req, err := server.newRequest("GET", part.Key.Path, part.Key.Query(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Del("Accept")
+
+	expectedStatus := http.StatusOK
+	if opts.Offset > 0 || opts.Length > 0 {
+		rangeHeader := "bytes=" + strconv.FormatInt(opts.Offset, 10) + "-"
+		if opts.Length > 0 {
+			rangeHeader += strconv.FormatInt(opts.Offset+opts.Length-1, 10)
+		}
+		req.Header.Set("Range", rangeHeader)
+		expectedStatus = http.StatusPartialContent
+	}
+
+	resp, err := doRequest(req.WithContext(ctx), expectedStatus, server.Retry)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	progress := &progressWriter{w: w, written: opts.Offset, size: part.Size, progress: opts.Progress}
+	if _, err := io.Copy(progress, resp.Body); err != nil {
+		return err
+	}
+
+	expected := opts.Length
+	if expected == 0 && part.Size > 0 {
+		expected = part.Size - opts.Offset
+	}
+	if expected > 0 && progress.written-opts.Offset != expected {
+		return fmt.Errorf("Downloaded %d bytes of %s, expected %d", progress.written-opts.Offset, part.Key, expected)
+	}
+
+	return nil
+}
+
+type progressWriter struct {
+	w        io.Writer
+	written  int64
+	size     int64
+	progress func(written, size int64)
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.written += int64(n)
+	if p.progress != nil {
+		p.progress(p.written, p.size)
+	}
+	return n, err
+}
@@ -0,0 +1,113 @@
+package plex
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testPartContent = "0123456789abcdefghij"
+
+var testPart = Part{
+	ID:   2147,
+	Key:  URLPath{url.URL{Path: "/library/parts/2147/file.mkv"}},
+	Size: int64(len(testPartContent)),
+}
+
+func startPartServer(t *testing.T) (*httptest.Server, Server) {
+	return startTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/library/parts/2147/file.mkv" || r.Header.Get("X-Plex-Token") != "authToken" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		http.ServeContent(w, r, "file.mkv", time.Time{}, strings.NewReader(testPartContent))
+	})
+}
+
+func TestPartURL(t *testing.T) {
+	result := makeTestServer().PartURL(testPart)
+	if result.String() != "http://server.com:4040/library/parts/2147/file.mkv?X-Plex-Token=authToken" {
+		t.Fatalf("Unexpected url %s", result)
+	}
+}
+
+func TestDownloadPart(t *testing.T) {
+	testServer, server := startPartServer(t)
+	defer testServer.Close()
+
+	var lastWritten, lastSize int64
+	opts := DownloadOptions{Progress: func(written, size int64) { lastWritten, lastSize = written, size }}
+
+	buf := &bytes.Buffer{}
+	if err := server.DownloadPart(context.Background(), testPart, buf, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != testPartContent {
+		t.Fatalf("Unexpected content %q", buf)
+	}
+	if lastWritten != testPart.Size || lastSize != testPart.Size {
+		t.Fatalf("Unexpected progress %d of %d", lastWritten, lastSize)
+	}
+}
+
+func TestDownloadPartResume(t *testing.T) {
+	testServer, server := startPartServer(t)
+	defer testServer.Close()
+
+	var lastWritten int64
+	opts := DownloadOptions{Offset: 10, Progress: func(written, size int64) { lastWritten = written }}
+
+	buf := &bytes.Buffer{}
+	if err := server.DownloadPart(context.Background(), testPart, buf, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != testPartContent[10:] {
+		t.Fatalf("Unexpected content %q", buf)
+	}
+	if lastWritten != testPart.Size {
+		t.Fatalf("Progress should include the offset, got %d", lastWritten)
+	}
+}
+
+func TestDownloadPartRange(t *testing.T) {
+	testServer, server := startPartServer(t)
+	defer testServer.Close()
+
+	buf := &bytes.Buffer{}
+	if err := server.DownloadPart(context.Background(), testPart, buf, DownloadOptions{Offset: 5, Length: 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != "567" {
+		t.Fatalf("Unexpected content %q", buf)
+	}
+}
+
+func TestDownloadPartSizeMismatch(t *testing.T) {
+	testServer, server := startPartServer(t)
+	defer testServer.Close()
+
+	part := testPart
+	part.Size = 100
+	if err := server.DownloadPart(context.Background(), part, &bytes.Buffer{}, DownloadOptions{}); err == nil {
+		t.Fatal("Should err when fewer bytes than the part's size are downloaded")
+	}
+}
+
+func TestDownloadPartFail(t *testing.T) {
+	testServer, server := startPartServer(t)
+	defer testServer.Close()
+
+	part := testPart
+	part.Key = URLPath{url.URL{Path: "/library/parts/1/missing.mkv"}}
+	if err := server.DownloadPart(context.Background(), part, &bytes.Buffer{}, DownloadOptions{}); err == nil {
+		t.Fatal("DownloadPart returned success when it received bad status code")
+	}
+}
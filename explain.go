@@ -0,0 +1,153 @@
+package plex
+
+import "strconv"
+
+// TranscodeReason is why the server had to change a stream instead of sending the original file
+type TranscodeReason int
+
+const (
+	// ReasonContainer means the streams were copied into a container the player supports
+	ReasonContainer TranscodeReason = iota + 1
+	ReasonVideoCodec
+	ReasonAudioCodec
+	// ReasonBitrate means the video was re-encoded in the same codec to fit a bitrate or resolution limit
+	ReasonBitrate
+	ReasonSubtitleBurn
+)
+
+func (reason TranscodeReason) String() string {
+	switch reason {
+	case ReasonContainer:
+		return "container"
+	case ReasonVideoCodec:
+		return "video codec"
+	case ReasonAudioCodec:
+		return "audio codec"
+	case ReasonBitrate:
+		return "bitrate limit"
+	case ReasonSubtitleBurn:
+		return "subtitle burn-in"
+	default:
+		return "unknown"
+	}
+}
+
+// CPUCost is a rough estimate of how hard a session works the server's CPU
+type CPUCost int
+
+const (
+	CPUCostNone CPUCost = iota
+	CPUCostLow
+	CPUCostMedium
+	CPUCostHigh
+)
+
+func (cost CPUCost) String() string {
+	switch cost {
+	case CPUCostNone:
+		return "none"
+	case CPUCostLow:
+		return "low"
+	case CPUCostMedium:
+		return "medium"
+	default:
+		return "high"
+	}
+}
+
+// TranscodeReport explains why a session isn't direct playing
+type TranscodeReport struct {
+	DirectPlay bool
+	Reasons    []TranscodeReason
+	CPUCost    CPUCost
+	// Advice suggests what the user could change to direct play, one line per reason
+	Advice []string
+}
+
+// ExplainTranscode works out why the server is transcoding a session from GetActivity, by comparing the
+// media being played with what the transcoder is producing
+func (v Video) ExplainTranscode() TranscodeReport {
+	session := v.TranscodeSession
+	if session == (TranscodeSession{}) {
+		return TranscodeReport{DirectPlay: true}
+	}
+
+	report := TranscodeReport{}
+	videoTranscode := session.VideoDecision == "transcode"
+	audioTranscode := session.AudioDecision == "transcode"
+	burn := v.burnsSubtitles()
+
+	sourceVideoCodec := firstNonEmpty(session.SourceVideoCodec, v.Media.VideoCodec)
+	sourceAudioCodec := firstNonEmpty(session.SourceAudioCodec, v.Media.AudioCodec)
+
+	if burn {
+		report.add(ReasonSubtitleBurn, "Turn subtitles off, or use a subtitle format the player can display such as SRT")
+	}
+	if videoTranscode && session.VideoCodec != "" && session.VideoCodec != sourceVideoCodec {
+		report.add(ReasonVideoCodec, playerName(v)+" can't play "+sourceVideoCodec+" video, try another app or "+
+			"convert the file to "+session.VideoCodec)
+	} else if videoTranscode && (!burn || session.Height < v.Media.HeightPx) {
+		report.add(ReasonBitrate, "Raise the streaming quality in "+playerName(v)+" to Original or Maximum, the "+
+			"file is "+strconv.Itoa(v.Media.Bitrate)+" kbps")
+	}
+	if audioTranscode {
+		report.add(ReasonAudioCodec, playerName(v)+" can't play "+sourceAudioCodec+" audio with "+
+			strconv.Itoa(v.Media.AudioChannels)+" channels, try passthrough or a different audio track")
+	}
+	if !videoTranscode && !audioTranscode && session.Container != "" && session.Container != v.Media.Container {
+		report.add(ReasonContainer, playerName(v)+" can't play "+v.Media.Container+" files, this is cheap for "+
+			"the server but remuxing the file to "+session.Container+" would avoid it")
+	}
+
+	report.CPUCost = v.estimateCPUCost(videoTranscode, burn)
+	return report
+}
+
+func (report *TranscodeReport) add(reason TranscodeReason, advice string) {
+	report.Reasons = append(report.Reasons, reason)
+	report.Advice = append(report.Advice, advice)
+}
+
+// estimateCPUCost treats copying streams and transcoding audio as cheap and transcoding video as expensive, most of
+// all for sources above 1080p or with subtitles burned in. Hardware transcoding is one step cheaper.
+func (v Video) estimateCPUCost(videoTranscode, burn bool) CPUCost {
+	if !videoTranscode {
+		return CPUCostLow
+	}
+
+	cost := CPUCostMedium
+	if burn || v.Media.HeightPx > 1080 {
+		cost = CPUCostHigh
+	}
+	if v.TranscodeSession.TranscodeHwRequested {
+		cost--
+	}
+	return cost
+}
+
+func (v Video) burnsSubtitles() bool {
+	if v.TranscodeSession.SubtitleDecision == "burn" {
+		return true
+	}
+	for _, part := range v.Media.Parts {
+		for _, stream := range part.Streams {
+			if stream.StreamType == StreamTypeSubtitle && stream.Decision == "burn" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func playerName(v Video) string {
+	return firstNonEmpty(v.Player.Product, v.Player.Title, "The player")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,100 @@
+package plex
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+)
+
+func TestExplainTranscode(t *testing.T) {
+	video := makeExpectedActivity()[0]
+
+	report := video.ExplainTranscode()
+
+	expectedReasons := []TranscodeReason{ReasonBitrate, ReasonAudioCodec}
+	if !reflect.DeepEqual(expectedReasons, report.Reasons) {
+		t.Fatalf("\nExpected: %v\n\nGot: %v", expectedReasons, report.Reasons)
+	}
+	if report.DirectPlay || report.CPUCost != CPUCostMedium || len(report.Advice) != 2 {
+		t.Fatalf("Unexpected report %+v", report)
+	}
+
+	expectedAdvice := "Plex for Android can't play ac3 audio with 6 channels, try passthrough or a different audio track"
+	if report.Advice[1] != expectedAdvice {
+		t.Fatalf("\nExpected: %s\n\nGot: %s", expectedAdvice, report.Advice[1])
+	}
+}
+
+func TestExplainTranscodeReasons(t *testing.T) {
+	tests := []struct {
+		name     string
+		media    Media
+		session  TranscodeSession
+		expected []TranscodeReason
+		cost     CPUCost
+	}{
+		{
+			name:     "video codec",
+			media:    Media{VideoCodec: "hevc", HeightPx: 2160, Container: "mkv"},
+			session:  TranscodeSession{VideoDecision: "transcode", AudioDecision: "copy", VideoCodec: "h264", Height: 1080},
+			expected: []TranscodeReason{ReasonVideoCodec},
+			cost:     CPUCostHigh,
+		},
+		{
+			name:     "hardware video codec",
+			media:    Media{VideoCodec: "hevc", HeightPx: 2160},
+			session:  TranscodeSession{VideoDecision: "transcode", VideoCodec: "h264", TranscodeHwRequested: true},
+			expected: []TranscodeReason{ReasonVideoCodec},
+			cost:     CPUCostMedium,
+		},
+		{
+			name:     "subtitles",
+			media:    Media{VideoCodec: "h264", HeightPx: 1080},
+			session:  TranscodeSession{VideoDecision: "transcode", VideoCodec: "h264", Height: 1080, SubtitleDecision: "burn"},
+			expected: []TranscodeReason{ReasonSubtitleBurn},
+			cost:     CPUCostHigh,
+		},
+		{
+			name:     "container",
+			media:    Media{VideoCodec: "h264", Container: "mkv"},
+			session:  TranscodeSession{VideoDecision: "copy", AudioDecision: "copy", Container: "mpegts"},
+			expected: []TranscodeReason{ReasonContainer},
+			cost:     CPUCostLow,
+		},
+	}
+
+	for _, test := range tests {
+		video := Video{Media: test.media, TranscodeSession: test.session}
+		report := video.ExplainTranscode()
+
+		if !reflect.DeepEqual(test.expected, report.Reasons) || report.CPUCost != test.cost {
+			t.Errorf("%s: expected %v costing %s, got %v costing %s",
+				test.name, test.expected, test.cost, report.Reasons, report.CPUCost)
+		}
+	}
+}
+
+func TestExplainTranscode4K(t *testing.T) {
+	resp := `<Video ratingKey="1751" type="movie" title="Dune">
+	  <Media videoResolution="4k" height="2160" width="3840" videoCodec="hevc" container="mkv" />
+	  <TranscodeSession videoDecision="transcode" audioDecision="copy" videoCodec="h264" height="1080" />
+	</Video>`
+
+	var video Video
+	if err := xml.Unmarshal([]byte(resp), &video); err != nil {
+		t.Fatal(err)
+	}
+
+	report := video.ExplainTranscode()
+	if !reflect.DeepEqual([]TranscodeReason{ReasonVideoCodec}, report.Reasons) || report.CPUCost != CPUCostHigh {
+		t.Fatalf("Unexpected report %+v", report)
+	}
+}
+
+func TestExplainDirectPlay(t *testing.T) {
+	report := Video{Media: Media{VideoCodec: "h264"}}.ExplainTranscode()
+
+	if !report.DirectPlay || len(report.Reasons) != 0 || report.CPUCost != CPUCostNone {
+		t.Fatalf("Unexpected report %+v", report)
+	}
+}
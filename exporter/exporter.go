@@ -0,0 +1,254 @@
+// Package exporter reports the state of Plex servers as Prometheus metrics. It writes the text exposition format
+// itself, so it can be scraped without depending on the Prometheus client library.
+//
+//	servers, err := user.GetServers()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	http.Handle("/metrics", exporter.New(servers...))
+//	log.Fatal(http.ListenAndServe(":9594", nil))
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	plex "github.com/sburba/goplex"
+)
+
+// Exporter collects metrics from its servers each time it is scraped
+type Exporter struct {
+	Servers []plex.Server
+	// Timeout is how long each server has to answer a scrape, including retries. A server that takes longer is
+	// reported as down. It defaults to 10 seconds.
+	Timeout time.Duration
+}
+
+func New(servers ...plex.Server) *Exporter {
+	return &Exporter{Servers: servers}
+}
+
+// ServeHTTP collects metrics from all of the servers and writes them in the text exposition format
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var buf bytes.Buffer
+	if err := WriteText(&buf, e.Collect(r.Context())); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write(buf.Bytes())
+}
+
+// Collect queries the servers concurrently and returns their metrics. A server that can't be reached within
+// Timeout is reported with plex_up 0 and no other metrics.
+func (e *Exporter) Collect(ctx context.Context) []*Family {
+	families := newFamilies()
+
+	timeout := e.timeout()
+	results := make([]serverMetrics, len(e.Servers))
+	var wg sync.WaitGroup
+	for i, server := range e.Servers {
+		wg.Add(1)
+		go func(i int, server plex.Server) {
+			defer wg.Done()
+			serverCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			results[i] = collectServer(serverCtx, server)
+		}(i, server)
+	}
+	wg.Wait()
+
+	for _, result := range results {
+		result.addTo(families)
+	}
+
+	return families.list()
+}
+
+const defaultTimeout = 10 * time.Second
+
+func (e *Exporter) timeout() time.Duration {
+	if e.Timeout <= 0 {
+		return defaultTimeout
+	}
+	return e.Timeout
+}
+
+type families struct {
+	up               *Family
+	sessions         *Family
+	sessionBandwidth *Family
+	libraryItems     *Family
+}
+
+func newFamilies() families {
+	return families{
+		up: &Family{
+			Name: "plex_up",
+			Help: "Whether the server answered the last scrape. server_id is the server's machine identifier.",
+		},
+		sessions: &Family{
+			Name: "plex_sessions",
+			Help: "Active playback sessions. decision is directplay, directstream or transcode.",
+		},
+		sessionBandwidth: &Family{
+			Name: "plex_session_bandwidth_bits_per_second",
+			Help: "Bandwidth the server has reserved for each session.",
+		},
+		libraryItems: &Family{
+			Name: "plex_library_items",
+			Help: "Top level items in each library section, e.g. movies or shows. section is the section's key.",
+		},
+	}
+}
+
+func (f families) list() []*Family {
+	return []*Family{f.up, f.sessions, f.sessionBandwidth, f.libraryItems}
+}
+
+// serverMetrics is what was learned about one server during a scrape
+type serverMetrics struct {
+	name     string
+	id       string
+	up       bool
+	sessions []plex.Video
+	sections []plex.Section
+	sizes    []int
+}
+
+func collectServer(ctx context.Context, server plex.Server) serverMetrics {
+	result := serverMetrics{name: serverName(server), id: server.ClientIdentifier}
+
+	sessions, err := server.GetActivityContext(ctx)
+	if err != nil {
+		return result
+	}
+	result.up = true
+	result.sessions = sessions
+
+	// Library sizes are best effort, a server that is up but slow to count is still reported
+	sections, err := server.GetSectionsContext(ctx)
+	if err != nil {
+		return result
+	}
+	for _, section := range sections {
+		size, err := section.GetSize(ctx)
+		if err != nil {
+			continue
+		}
+		result.sections = append(result.sections, section)
+		result.sizes = append(result.sizes, size)
+	}
+
+	return result
+}
+
+func (result serverMetrics) addTo(f families) {
+	if !result.up {
+		f.up.add(0, Labels{"server": result.name, "server_id": result.id})
+		return
+	}
+	f.up.add(1, Labels{"server": result.name, "server_id": result.id})
+
+	libraries := map[string]string{}
+	for i, section := range result.sections {
+		libraries[section.Key] = section.Title
+		f.libraryItems.add(float64(result.sizes[i]), Labels{
+			"server":    result.name,
+			"server_id": result.id,
+			"library":   section.Title,
+			"section":   section.Key,
+			"type":      section.Type,
+		})
+	}
+
+	counts := map[sessionKey]int{}
+	for _, video := range result.sessions {
+		key := sessionKey{
+			user:     video.User.Title,
+			player:   video.Player.Title,
+			library:  libraries[string(video.LibrarySectionID)],
+			section:  string(video.LibrarySectionID),
+			decision: decision(video),
+		}
+		counts[key]++
+
+		f.sessionBandwidth.add(float64(video.Session.Bandwidth)*1000, Labels{
+			"server":    result.name,
+			"server_id": result.id,
+			"user":      key.user,
+			"player":    key.player,
+			"library":   key.library,
+			"section":   key.section,
+			"location":  video.Session.Location,
+			"session":   video.Session.ID,
+		})
+	}
+
+	keys := make([]sessionKey, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].less(keys[j]) })
+
+	for _, key := range keys {
+		f.sessions.add(float64(counts[key]), Labels{
+			"server":    result.name,
+			"server_id": result.id,
+			"user":      key.user,
+			"player":    key.player,
+			"library":   key.library,
+			"section":   key.section,
+			"decision":  key.decision,
+		})
+	}
+}
+
+type sessionKey struct {
+	user     string
+	player   string
+	library  string
+	section  string
+	decision string
+}
+
+func (key sessionKey) less(other sessionKey) bool {
+	if key.user != other.user {
+		return key.user < other.user
+	}
+	if key.player != other.player {
+		return key.player < other.player
+	}
+	if key.library != other.library {
+		return key.library < other.library
+	}
+	if key.section != other.section {
+		return key.section < other.section
+	}
+	return key.decision < other.decision
+}
+
+// decision classifies a session the way the Plex dashboard does
+func decision(video plex.Video) string {
+	session := video.TranscodeSession
+	switch {
+	case session == (plex.TranscodeSession{}):
+		return "directplay"
+	case session.VideoDecision == "transcode" || session.AudioDecision == "transcode":
+		return "transcode"
+	default:
+		return "directstream"
+	}
+}
+
+func serverName(server plex.Server) string {
+	if server.Name != "" {
+		return server.Name
+	}
+	return server.ClientIdentifier
+}
@@ -0,0 +1,165 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	plex "github.com/sburba/goplex"
+)
+
+const testSessions = `<MediaContainer size="3">
+	<Video librarySectionID="1" title="Episode 21" type="episode">
+		<User id="1" title="owner" />
+		<Player title="Living Room" />
+		<Session id="a" bandwidth="4000" location="lan" />
+	</Video>
+	<Video librarySectionID="1" title="Episode 22" type="episode">
+		<User id="1" title="owner" />
+		<Player title="Living Room" />
+		<Session id="b" bandwidth="2000" location="lan" />
+	</Video>
+	<Video librarySectionID="2" title="Movie" type="movie">
+		<User id="22" title="friend" />
+		<Player title="Phone" />
+		<TranscodeSession videoDecision="transcode" audioDecision="copy" />
+		<Session id="c" bandwidth="1500" location="wan" />
+	</Video>
+</MediaContainer>`
+
+const testSections = `<MediaContainer size="2">
+	<Directory key="1" type="show" title="TV Shows" />
+	<Directory key="2" type="movie" title="Movies" />
+</MediaContainer>`
+
+func startTestServer(t *testing.T, name, id, sections string) (*httptest.Server, plex.Server) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/status/sessions":
+			w.Write([]byte(testSessions))
+		case "/library/sections":
+			w.Write([]byte(sections))
+		case "/library/sections/1/all":
+			w.Write([]byte(`<MediaContainer size="0" totalSize="12"></MediaContainer>`))
+		case "/library/sections/2/all":
+			w.Write([]byte(`<MediaContainer size="0" totalSize="345"></MediaContainer>`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	address, err := url.Parse(testServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := plex.Server{Device: plex.Device{Name: name, ClientIdentifier: id, PublicAddress: plex.HTTPURL{URL: *address}}}
+	return testServer, server
+}
+
+func TestExporter(t *testing.T) {
+	testServer, server := startTestServer(t, "Home", "home-id", testSections)
+	defer testServer.Close()
+
+	downServer, down := startTestServer(t, "Cabin", "cabin-id", testSections)
+	downServer.Close()
+
+	// Don't wait for retries to the closed server
+	down.Retry = plex.RetryPolicy{MaxAttempts: 1}
+
+	recorder := httptest.NewRecorder()
+	New(server, down).ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	expected := `# HELP plex_up Whether the server answered the last scrape. server_id is the server's machine identifier.
+# TYPE plex_up gauge
+plex_up{server="Home",server_id="home-id"} 1
+plex_up{server="Cabin",server_id="cabin-id"} 0
+# HELP plex_sessions Active playback sessions. decision is directplay, directstream or transcode.
+# TYPE plex_sessions gauge
+plex_sessions{decision="transcode",library="Movies",player="Phone",section="2",server="Home",server_id="home-id",user="friend"} 1
+plex_sessions{decision="directplay",library="TV Shows",player="Living Room",section="1",server="Home",server_id="home-id",user="owner"} 2
+# HELP plex_session_bandwidth_bits_per_second Bandwidth the server has reserved for each session.
+# TYPE plex_session_bandwidth_bits_per_second gauge
+plex_session_bandwidth_bits_per_second{library="TV Shows",location="lan",player="Living Room",section="1",server="Home",server_id="home-id",session="a",user="owner"} 4e+06
+plex_session_bandwidth_bits_per_second{library="TV Shows",location="lan",player="Living Room",section="1",server="Home",server_id="home-id",session="b",user="owner"} 2e+06
+plex_session_bandwidth_bits_per_second{library="Movies",location="wan",player="Phone",section="2",server="Home",server_id="home-id",session="c",user="friend"} 1.5e+06
+# HELP plex_library_items Top level items in each library section, e.g. movies or shows. section is the section's key.
+# TYPE plex_library_items gauge
+plex_library_items{library="TV Shows",section="1",server="Home",server_id="home-id",type="show"} 12
+plex_library_items{library="Movies",section="2",server="Home",server_id="home-id",type="movie"} 345
+`
+	if recorder.Body.String() != expected {
+		t.Fatalf("\nExpected:\n%s\nGot:\n%s", expected, recorder.Body)
+	}
+
+	if !strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Fatalf("Unexpected content type %s", recorder.Header().Get("Content-Type"))
+	}
+}
+
+func TestExporterDuplicateNames(t *testing.T) {
+	sameTitles := `<MediaContainer size="2">
+	<Directory key="1" type="movie" title="Movies" />
+	<Directory key="2" type="movie" title="Movies" />
+</MediaContainer>`
+
+	firstServer, first := startTestServer(t, "Home", "home-1", sameTitles)
+	defer firstServer.Close()
+	secondServer, second := startTestServer(t, "Home", "home-2", sameTitles)
+	defer secondServer.Close()
+
+	var buf bytes.Buffer
+	if err := WriteText(&buf, New(first, second).Collect(context.Background())); err != nil {
+		t.Fatal(err)
+	}
+
+	series := map[string]bool{}
+	items := 0
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		name := line[:strings.LastIndex(line, " ")]
+		if series[name] {
+			t.Fatalf("Duplicate series %s in\n%s", name, buf.String())
+		}
+		series[name] = true
+		if strings.HasPrefix(name, "plex_library_items") {
+			items++
+		}
+	}
+	if items != 4 {
+		t.Fatalf("Expected both sections of both servers, got\n%s", buf.String())
+	}
+}
+
+func TestExporterTimeout(t *testing.T) {
+	slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer slowServer.Close()
+
+	address, err := url.Parse(slowServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slow := plex.Server{Device: plex.Device{Name: "Slow", PublicAddress: plex.HTTPURL{URL: *address}}}
+
+	exporter := New(slow)
+	exporter.Timeout = 50 * time.Millisecond
+	started := time.Now()
+	families := exporter.Collect(context.Background())
+
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Fatalf("Scrape took %s, the timeout is %s", elapsed, exporter.Timeout)
+	}
+	up := families[0].Samples
+	if len(up) != 1 || up[0].Value != 0 || up[0].Labels["server"] != "Slow" {
+		t.Fatalf("Expected the slow server to be down, got %+v", up)
+	}
+}
@@ -0,0 +1,81 @@
+package exporter
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Labels are the labels of a sample, by name
+type Labels map[string]string
+
+// Sample is one value of a metric
+type Sample struct {
+	Labels Labels
+	Value  float64
+}
+
+// Family is a metric and all of its samples. Only gauges are produced, everything the exporter reports is
+// a snapshot of the servers at the time of the scrape.
+type Family struct {
+	Name    string
+	Help    string
+	Samples []Sample
+}
+
+func (family *Family) add(value float64, labels Labels) {
+	family.Samples = append(family.Samples, Sample{Labels: labels, Value: value})
+}
+
+// WriteText writes families in the Prometheus text exposition format
+func WriteText(w io.Writer, families []*Family) error {
+	buf := bufio.NewWriter(w)
+
+	for _, family := range families {
+		buf.WriteString("# HELP " + family.Name + " " + escapeHelp(family.Help) + "\n")
+		buf.WriteString("# TYPE " + family.Name + " gauge\n")
+
+		for _, sample := range family.Samples {
+			buf.WriteString(family.Name)
+			writeLabels(buf, sample.Labels)
+			buf.WriteString(" " + strconv.FormatFloat(sample.Value, 'g', -1, 64) + "\n")
+		}
+	}
+
+	return buf.Flush()
+}
+
+// writeLabels writes labels sorted by name, so the output is stable between scrapes
+func writeLabels(buf *bufio.Writer, labels Labels) {
+	if len(labels) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	buf.WriteString("{")
+	for i, name := range names {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString(name + `="` + escapeLabelValue(labels[name]) + `"`)
+	}
+	buf.WriteString("}")
+}
+
+var helpEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
+
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
@@ -0,0 +1,26 @@
+package exporter
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteText(t *testing.T) {
+	family := &Family{Name: "plex_test", Help: "A help\\text\nover two lines."}
+	family.add(1, nil)
+	family.add(0.5, Labels{"user": `Aunt "May"`, "player": "Living\\Room\nTV"})
+
+	buf := &bytes.Buffer{}
+	if err := WriteText(buf, []*Family{family}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `# HELP plex_test A help\\text\nover two lines.
+# TYPE plex_test gauge
+plex_test 1
+plex_test{player="Living\\Room\nTV",user="Aunt \"May\""} 0.5
+`
+	if buf.String() != expected {
+		t.Fatalf("\nExpected:\n%s\nGot:\n%s", expected, buf)
+	}
+}
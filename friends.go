@@ -0,0 +1,237 @@
+package plex
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/url"
+	"strconv"
+)
+
+type friendsResp struct {
+	XMLName xml.Name `xml:"MediaContainer"`
+	Friends []Friend `xml:"User"`
+}
+
+type invitesResp struct {
+	XMLName xml.Name `xml:"MediaContainer"`
+	Invites []Invite `xml:"Invite"`
+}
+
+type serverSectionsResp struct {
+	XMLName  xml.Name        `xml:"MediaContainer"`
+	Sections []ServerSection `xml:"Server>Section"`
+}
+
+// Friend is a plex.tv user the account owner shares with
+type Friend struct {
+	ID                int64          `xml:"id,attr"`
+	Title             string         `xml:"title,attr"`
+	Username          string         `xml:"username,attr"`
+	Email             string         `xml:"email,attr"`
+	Thumb             HTTPURL        `xml:"thumb,attr"`
+	Home              IntAsBool      `xml:"home,attr"`
+	Restricted        IntAsBool      `xml:"restricted,attr"`
+	AllowSync         IntAsBool      `xml:"allowSync,attr"`
+	AllowCameraUpload IntAsBool      `xml:"allowCameraUpload,attr"`
+	AllowChannels     IntAsBool      `xml:"allowChannels,attr"`
+	FilterMovies      string         `xml:"filterMovies,attr"`
+	FilterTelevision  string         `xml:"filterTelevision,attr"`
+	FilterMusic       string         `xml:"filterMusic,attr"`
+	Servers           []FriendServer `xml:"Server"`
+}
+
+// FriendServer is a server shared with a friend
+type FriendServer struct {
+	ID                int64     `xml:"id,attr"`
+	ServerID          int64     `xml:"serverId,attr"`
+	MachineIdentifier string    `xml:"machineIdentifier,attr"`
+	Name              string    `xml:"name,attr"`
+	LastSeenAt        UnixTime  `xml:"lastSeenAt,attr"`
+	NumLibraries      int       `xml:"numLibraries,attr"`
+	AllLibraries      IntAsBool `xml:"allLibraries,attr"`
+	Owned             IntAsBool `xml:"owned,attr"`
+	Pending           IntAsBool `xml:"pending,attr"`
+}
+
+type Invite struct {
+	ID           int64     `xml:"id,attr"`
+	Username     string    `xml:"username,attr"`
+	Email        string    `xml:"email,attr"`
+	FriendlyName string    `xml:"friendlyName,attr"`
+	Thumb        HTTPURL   `xml:"thumb,attr"`
+	CreatedAt    UnixTime  `xml:"createdAt,attr"`
+	Friend       IntAsBool `xml:"friend,attr"`
+	Home         IntAsBool `xml:"home,attr"`
+	Server       IntAsBool `xml:"server,attr"`
+}
+
+// ServerSection is a library section as plex.tv knows it. Sharing refers to sections by their plex.tv ID, which is
+// not the same as the Key the server uses.
+type ServerSection struct {
+	ID     int       `xml:"id,attr"`
+	Key    string    `xml:"key,attr"`
+	Title  string    `xml:"title,attr"`
+	Type   string    `xml:"type,attr"`
+	Shared IntAsBool `xml:"shared,attr"`
+}
+
+// ShareSettings controls what a friend may do with the libraries shared with them. Filters use the same syntax as
+// the web app, e.g. label=Kids or contentRating=G|PG.
+type ShareSettings struct {
+	AllowSync         bool
+	AllowCameraUpload bool
+	AllowChannels     bool
+	FilterMovies      string
+	FilterTelevision  string
+	FilterMusic       string
+}
+
+type sharedServerRequest struct {
+	ServerID        string          `json:"server_id"`
+	SharedServer    sharedServer    `json:"shared_server"`
+	SharingSettings *sharingSetting `json:"sharing_settings,omitempty"`
+}
+
+type sharedServer struct {
+	LibrarySectionIDs []int  `json:"library_section_ids"`
+	InvitedEmail      string `json:"invited_email,omitempty"`
+	InvitedID         int64  `json:"invited_id,omitempty"`
+}
+
+type sharingSetting struct {
+	AllowSync         string `json:"allowSync"`
+	AllowCameraUpload string `json:"allowCameraUpload"`
+	AllowChannels     string `json:"allowChannels"`
+	FilterMovies      string `json:"filterMovies"`
+	FilterTelevision  string `json:"filterTelevision"`
+	FilterMusic       string `json:"filterMusic"`
+}
+
+func (user User) GetFriends() ([]Friend, error) {
+	resp := &friendsResp{}
+	if err := user.fetch("GET", "/api/users", nil, resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Friends, nil
+}
+
+// GetServerSections returns the sections of server that can be shared
+func (user User) GetServerSections(server Server) ([]ServerSection, error) {
+	resp := &serverSectionsResp{}
+	if err := user.fetch("GET", "/api/servers/"+server.ClientIdentifier, nil, resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Sections, nil
+}
+
+// InviteFriend invites someone who is not yet a friend, by username or email, to the given sections of server
+func (user User) InviteFriend(usernameOrEmail string, server Server, sectionIDs []int, settings ShareSettings) error {
+	body := sharedServerRequest{
+		ServerID: server.ClientIdentifier,
+		SharedServer: sharedServer{
+			LibrarySectionIDs: sectionIDs,
+			InvitedEmail:      usernameOrEmail,
+		},
+		SharingSettings: settings.sharingSetting(),
+	}
+
+	return user.fetchJSON("POST", "/api/servers/"+server.ClientIdentifier+"/shared_servers", body, nil)
+}
+
+// ShareSections replaces the sections of server shared with friend. Passing no sections leaves the server shared
+// but with nothing in it, use RemoveFriend to stop sharing entirely.
+func (user User) ShareSections(friend Friend, server Server, sectionIDs []int) error {
+	if sectionIDs == nil {
+		sectionIDs = []int{}
+	}
+
+	body := sharedServerRequest{
+		ServerID:     server.ClientIdentifier,
+		SharedServer: sharedServer{LibrarySectionIDs: sectionIDs},
+	}
+	path := "/api/servers/" + server.ClientIdentifier + "/shared_servers"
+
+	for _, shared := range friend.Servers {
+		if shared.MachineIdentifier == server.ClientIdentifier {
+			return user.fetchJSON("PUT", path+"/"+strconv.FormatInt(shared.ID, 10), body, nil)
+		}
+	}
+
+	// The server isn't shared with the friend yet
+	body.SharedServer.InvitedID = friend.ID
+	return user.fetchJSON("POST", path, body, nil)
+}
+
+func (user User) UpdateShareSettings(friend Friend, settings ShareSettings) error {
+	params := url.Values{}
+	params.Set("allowSync", boolParam(settings.AllowSync))
+	params.Set("allowCameraUpload", boolParam(settings.AllowCameraUpload))
+	params.Set("allowChannels", boolParam(settings.AllowChannels))
+	params.Set("filterMovies", settings.FilterMovies)
+	params.Set("filterTelevision", settings.FilterTelevision)
+	params.Set("filterMusic", settings.FilterMusic)
+
+	return user.fetch("PUT", "/api/friends/"+strconv.FormatInt(friend.ID, 10), params, nil)
+}
+
+// RemoveFriend stops sharing all servers with friend and removes them as a friend
+func (user User) RemoveFriend(friend Friend) error {
+	return user.fetch("DELETE", "/api/friends/"+strconv.FormatInt(friend.ID, 10), nil, nil)
+}
+
+// GetSentInvites returns the invitations the user has sent that haven't been accepted
+func (user User) GetSentInvites() ([]Invite, error) {
+	resp := &invitesResp{}
+	if err := user.fetch("GET", "/api/invites/requested", nil, resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Invites, nil
+}
+
+// GetReceivedInvites returns the invitations other users have sent the user
+func (user User) GetReceivedInvites() ([]Invite, error) {
+	resp := &invitesResp{}
+	if err := user.fetch("GET", "/api/invites/requests", nil, resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Invites, nil
+}
+
+// AcceptInvite accepts an invitation from GetReceivedInvites
+func (user User) AcceptInvite(invite Invite) error {
+	if invite.ID == 0 {
+		return errors.New("Invite has no ID")
+	}
+	return user.fetch("PUT", "/api/invites/requests/"+strconv.FormatInt(invite.ID, 10), invite.params(), nil)
+}
+
+// CancelInvite withdraws an invitation from GetSentInvites
+func (user User) CancelInvite(invite Invite) error {
+	if invite.ID == 0 {
+		return errors.New("Invite has no ID")
+	}
+	return user.fetch("DELETE", "/api/invites/requested/"+strconv.FormatInt(invite.ID, 10), invite.params(), nil)
+}
+
+func (invite Invite) params() url.Values {
+	params := url.Values{}
+	params.Set("friend", boolParam(bool(invite.Friend)))
+	params.Set("server", boolParam(bool(invite.Server)))
+	params.Set("home", boolParam(bool(invite.Home)))
+	return params
+}
+
+func (settings ShareSettings) sharingSetting() *sharingSetting {
+	return &sharingSetting{
+		AllowSync:         boolParam(settings.AllowSync),
+		AllowCameraUpload: boolParam(settings.AllowCameraUpload),
+		AllowChannels:     boolParam(settings.AllowChannels),
+		FilterMovies:      settings.FilterMovies,
+		FilterTelevision:  settings.FilterTelevision,
+		FilterMusic:       settings.FilterMusic,
+	}
+}
@@ -0,0 +1,208 @@
+package plex
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var testOwner = User{AuthToken: "authToken"}
+
+func TestGetFriendsSuccess(t *testing.T) {
+	resp := `<?xml version="1.0" encoding="UTF-8"?>
+	<MediaContainer friendlyName="myPlex" identifier="com.plexapp.plugins.myplex" machineIdentifier="abc" totalSize="1" size="1">
+	  <User id="555" title="Aunt May" username="auntmay" email="may@address.com" recommendationsPlaylistId="" thumb="https://plex.tv/users/abc/avatar" protected="0" home="0" allowTuners="0" allowSync="1" allowCameraUpload="0" allowChannels="0" allowSubtitleAdmin="0" filterAll="" filterMovies="label=Family" filterMusic="" filterPhotos="" filterTelevision="" restricted="0">
+	    <Server id="9001" serverId="42" machineIdentifier="machineID" name="Server" lastSeenAt="1430601269" numLibraries="2" allLibraries="0" owned="1" pending="0" />
+	  </User>
+	</MediaContainer>`
+
+	client = makeFakeClient(t, http.StatusOK, resp, makeServerRequest(t, "GET", "https://plex.tv/api/users"))
+
+	result, err := testOwner.GetFriends()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Friend{
+		Friend{
+			ID:           555,
+			Title:        "Aunt May",
+			Username:     "auntmay",
+			Email:        "may@address.com",
+			Thumb:        HTTPURL{url.URL{Scheme: "https", Host: "plex.tv", Path: "/users/abc/avatar"}},
+			AllowSync:    true,
+			FilterMovies: "label=Family",
+			Servers: []FriendServer{
+				FriendServer{
+					ID:                9001,
+					ServerID:          42,
+					MachineIdentifier: "machineID",
+					Name:              "Server",
+					LastSeenAt:        UnixTime{time.Unix(1430601269, 0)},
+					NumLibraries:      2,
+					Owned:             true,
+				},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("\nExpected: %+v\n\nGot: %+v", expected, result)
+	}
+}
+
+func TestGetFriendsFail(t *testing.T) {
+	client = makeFakeClient(t, http.StatusUnauthorized, "", makeServerRequest(t, "GET", "https://plex.tv/api/users"))
+
+	if _, err := testOwner.GetFriends(); err == nil {
+		t.Fatal("Should err when plex.tv returns 401")
+	}
+}
+
+func TestGetServerSectionsSuccess(t *testing.T) {
+	resp := `<?xml version="1.0" encoding="UTF-8"?>
+	<MediaContainer friendlyName="myPlex" identifier="com.plexapp.plugins.myplex" size="1">
+	  <Server name="Server" address="1.2.3.4" port="32400" version="1.0" scheme="http" host="1.2.3.4" localAddresses="192.168.1.2" machineIdentifier="machineID" createdAt="1394924489" updatedAt="1430601269" owned="1" synced="0">
+	    <Section id="101" key="1" type="movie" title="Movies" />
+	    <Section id="102" key="2" type="show" title="TV Shows" />
+	  </Server>
+	</MediaContainer>`
+
+	client = makeFakeClient(t, http.StatusOK, resp, makeServerRequest(t, "GET", "https://plex.tv/api/servers/machineID"))
+
+	result, err := testOwner.GetServerSections(makeTestServer())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ServerSection{
+		ServerSection{ID: 101, Key: "1", Type: "movie", Title: "Movies"},
+		ServerSection{ID: 102, Key: "2", Type: "show", Title: "TV Shows"},
+	}
+
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("\nExpected: %+v\n\nGot: %+v", expected, result)
+	}
+}
+
+func TestInviteFriendSuccess(t *testing.T) {
+	expectedReq := makeServerRequest(t, "POST", "https://plex.tv/api/servers/machineID/shared_servers")
+	expectedReq.Header.Add("Content-Type", "application/json")
+	expectedBody := `{"server_id":"machineID","shared_server":{"library_section_ids":[101],"invited_email":"may@address.com"},` +
+		`"sharing_settings":{"allowSync":"1","allowCameraUpload":"0","allowChannels":"0","filterMovies":"label=Family",` +
+		`"filterTelevision":"","filterMusic":""}}`
+	client = makeFakeBodyClient(t, http.StatusOK, "", expectedReq, expectedBody)
+
+	settings := ShareSettings{AllowSync: true, FilterMovies: "label=Family"}
+	if err := testOwner.InviteFriend("may@address.com", makeTestServer(), []int{101}, settings); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestShareSectionsUpdatesExistingShare(t *testing.T) {
+	expectedReq := makeServerRequest(t, "PUT", "https://plex.tv/api/servers/machineID/shared_servers/9001")
+	expectedReq.Header.Add("Content-Type", "application/json")
+	expectedBody := `{"server_id":"machineID","shared_server":{"library_section_ids":[101,102]}}`
+	client = makeFakeBodyClient(t, http.StatusOK, "", expectedReq, expectedBody)
+
+	friend := Friend{ID: 555, Servers: []FriendServer{FriendServer{ID: 9001, MachineIdentifier: "machineID"}}}
+	if err := testOwner.ShareSections(friend, makeTestServer(), []int{101, 102}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestShareSectionsCreatesShare(t *testing.T) {
+	expectedReq := makeServerRequest(t, "POST", "https://plex.tv/api/servers/machineID/shared_servers")
+	expectedReq.Header.Add("Content-Type", "application/json")
+	expectedBody := `{"server_id":"machineID","shared_server":{"library_section_ids":[],"invited_id":555}}`
+	client = makeFakeBodyClient(t, http.StatusOK, "", expectedReq, expectedBody)
+
+	friend := Friend{ID: 555, Servers: []FriendServer{FriendServer{ID: 9002, MachineIdentifier: "otherMachine"}}}
+	if err := testOwner.ShareSections(friend, makeTestServer(), nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUpdateShareSettingsSuccess(t *testing.T) {
+	expectedReq := makeServerRequest(t, "PUT", "https://plex.tv/api/friends/555?"+
+		"allowCameraUpload=0&allowChannels=1&allowSync=0&filterMovies=&filterMusic=&filterTelevision=contentRating%3DTV-Y")
+	client = makeFakeClient(t, http.StatusOK, "", expectedReq)
+
+	settings := ShareSettings{AllowChannels: true, FilterTelevision: "contentRating=TV-Y"}
+	if err := testOwner.UpdateShareSettings(Friend{ID: 555}, settings); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRemoveFriendFail(t *testing.T) {
+	client = makeFakeClient(t, http.StatusNotFound, "", makeServerRequest(t, "DELETE", "https://plex.tv/api/friends/555"))
+
+	if err := testOwner.RemoveFriend(Friend{ID: 555}); err == nil {
+		t.Fatal("Should err when plex.tv returns 404")
+	}
+}
+
+func TestGetSentInvitesSuccess(t *testing.T) {
+	resp := `<?xml version="1.0" encoding="UTF-8"?>
+	<MediaContainer friendlyName="myPlex" identifier="com.plexapp.plugins.myplex" size="1">
+	  <Invite id="777" createdAt="1430601269" friend="1" home="0" server="1" username="cousin" email="cousin@address.com" thumb="https://plex.tv/users/def/avatar" friendlyName="Cousin">
+	    <Server name="Server" numLibraries="1" />
+	  </Invite>
+	</MediaContainer>`
+
+	client = makeFakeClient(t, http.StatusOK, resp, makeServerRequest(t, "GET", "https://plex.tv/api/invites/requested"))
+
+	result, err := testOwner.GetSentInvites()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Invite{
+		Invite{
+			ID:           777,
+			Username:     "cousin",
+			Email:        "cousin@address.com",
+			FriendlyName: "Cousin",
+			Thumb:        HTTPURL{url.URL{Scheme: "https", Host: "plex.tv", Path: "/users/def/avatar"}},
+			CreatedAt:    UnixTime{time.Unix(1430601269, 0)},
+			Friend:       true,
+			Server:       true,
+		},
+	}
+
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("\nExpected: %+v\n\nGot: %+v", expected, result)
+	}
+}
+
+func TestGetReceivedInvitesFail(t *testing.T) {
+	client = makeFakeClient(t, http.StatusUnauthorized, "", makeServerRequest(t, "GET", "https://plex.tv/api/invites/requests"))
+
+	if _, err := testOwner.GetReceivedInvites(); err == nil {
+		t.Fatal("Should err when plex.tv returns 401")
+	}
+}
+
+func TestAcceptInviteSuccess(t *testing.T) {
+	expectedReq := makeServerRequest(t, "PUT", "https://plex.tv/api/invites/requests/777?friend=1&home=0&server=1")
+	client = makeFakeClient(t, http.StatusOK, "", expectedReq)
+
+	if err := testOwner.AcceptInvite(Invite{ID: 777, Friend: true, Server: true}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCancelInviteSuccess(t *testing.T) {
+	expectedReq := makeServerRequest(t, "DELETE", "https://plex.tv/api/invites/requested/777?friend=1&home=0&server=0")
+	client = makeFakeClient(t, http.StatusOK, "", expectedReq)
+
+	if err := testOwner.CancelInvite(Invite{ID: 777, Friend: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := testOwner.CancelInvite(Invite{}); err == nil {
+		t.Fatal("Should err when the invite has no ID")
+	}
+}
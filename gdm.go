@@ -0,0 +1,127 @@
+package plex
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// G'Day Mate (GDM) is the protocol Plex uses to find servers and players on the local network without plex.tv.
+// A search message is multicast to servers or broadcast to players, and every listening device answers with an
+// HTTP style list of headers.
+
+// Hooks to override for tests
+var gdmServerAddress = "239.0.0.250:32414"
+var gdmPlayerAddress = "255.255.255.255:32412"
+
+const gdmSearchMessage = "M-SEARCH * HTTP/1.1\r\n\r\n"
+
+// DiscoverServers finds the Plex Media Servers on the local network that answer within timeout. The returned
+// servers have no Owner, set one if the server requires an auth token.
+func DiscoverServers(timeout time.Duration) ([]Server, error) {
+	devices, err := discover(gdmServerAddress, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var servers []Server
+	for _, device := range devices {
+		server, err := device.toServer()
+		if err == nil {
+			servers = append(servers, server)
+		}
+	}
+
+	return servers, nil
+}
+
+// DiscoverPlayers finds the players on the local network that answer within timeout
+func DiscoverPlayers(timeout time.Duration) ([]Device, error) {
+	return discover(gdmPlayerAddress, timeout)
+}
+
+func discover(address string, timeout time.Duration) ([]Device, error) {
+	addr, err := net.ResolveUDPAddr("udp4", address)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.ListenUDP("udp4", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.WriteTo([]byte(gdmSearchMessage), addr); err != nil {
+		return nil, err
+	}
+
+	if err := conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return nil, err
+	}
+
+	var devices []Device
+	seen := map[string]bool{}
+	buf := make([]byte, 4096)
+	for {
+		n, from, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				return devices, nil
+			}
+			return devices, err
+		}
+
+		device, err := parseGDMResponse(buf[:n], from.IP)
+		if err != nil {
+			continue
+		}
+
+		// Devices answer once per interface, but without an identifier there's no telling two devices apart
+		if device.ClientIdentifier != "" {
+			if seen[device.ClientIdentifier] {
+				continue
+			}
+			seen[device.ClientIdentifier] = true
+		}
+		devices = append(devices, device)
+	}
+}
+
+func parseGDMResponse(msg []byte, ip net.IP) (Device, error) {
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(msg)), nil)
+	if err != nil {
+		return Device{}, err
+	}
+	resp.Body.Close()
+
+	header := resp.Header
+	device := Device{
+		Name:             header.Get("Name"),
+		ClientIdentifier: header.Get("Resource-Identifier"),
+		Product:          header.Get("Product"),
+	}
+
+	switch header.Get("Content-Type") {
+	case "plex/media-server":
+		device.Provides = CommaSeperatedSlice{"server"}
+		if device.Product == "" {
+			device.Product = "Plex Media Server"
+		}
+	case "plex/media-player":
+		device.Provides = CommaSeperatedSlice{"player"}
+	}
+
+	port := header.Get("Port")
+	if port == "" {
+		port = "32400"
+	}
+	address := HTTPURL{url.URL{Scheme: "http", Host: net.JoinHostPort(ip.String(), port)}}
+	device.PublicAddress = address
+	device.Connections = []Connection{Connection{address}}
+
+	return device, nil
+}
@@ -0,0 +1,147 @@
+package plex
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// startGDMResponder answers every search it receives with each of the given responses
+func startGDMResponder(t *testing.T, responses ...string) (string, func()) {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, from, err := conn.ReadFromUDP(buf)
+			if err != nil {
+				return
+			}
+			if string(buf[:n]) != gdmSearchMessage {
+				t.Errorf("Unexpected search message %q", buf[:n])
+				continue
+			}
+			for _, resp := range responses {
+				conn.WriteToUDP([]byte(resp), from)
+			}
+		}
+	}()
+
+	return conn.LocalAddr().String(), func() { conn.Close() }
+}
+
+func TestDiscoverServersSuccess(t *testing.T) {
+	resp := "HTTP/1.0 200 OK\r\n" +
+		"Content-Type: plex/media-server\r\n" +
+		"Resource-Identifier: clientIdentifier\r\n" +
+		"Name: Server\r\n" +
+		"Port: 32400\r\n" +
+		"Updated-At: 1430601269\r\n" +
+		"Version: 0.9.11.17.986-269b82b\r\n\r\n"
+
+	address, stop := startGDMResponder(t, resp, resp)
+	defer stop()
+	defer func(original string) { gdmServerAddress = original }(gdmServerAddress)
+	gdmServerAddress = address
+
+	result, err := DiscoverServers(200 * time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	connection := HTTPURL{url.URL{Scheme: "http", Host: "127.0.0.1:32400"}}
+	expected := []Server{
+		Server{
+			Device: Device{
+				Name:             "Server",
+				ClientIdentifier: "clientIdentifier",
+				PublicAddress:    connection,
+				Product:          "Plex Media Server",
+				Provides:         []string{"server"},
+				Connections:      []Connection{Connection{connection}},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("\nExpected: %+v\n\nGot: %+v", expected, result)
+	}
+}
+
+func TestDiscoverPlayersSuccess(t *testing.T) {
+	resp := "HTTP/1.0 200 OK\r\n" +
+		"Content-Type: plex/media-player\r\n" +
+		"Resource-Identifier: roku-1\r\n" +
+		"Name: Living Room\r\n" +
+		"Port: 32500\r\n" +
+		"Product: Plex for Roku\r\n" +
+		"Protocol-Capabilities: timeline,playback,navigation\r\n\r\n"
+
+	address, stop := startGDMResponder(t, resp, "not a gdm response")
+	defer stop()
+	defer func(original string) { gdmPlayerAddress = original }(gdmPlayerAddress)
+	gdmPlayerAddress = address
+
+	result, err := DiscoverPlayers(200 * time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	connection := HTTPURL{url.URL{Scheme: "http", Host: "127.0.0.1:32500"}}
+	expected := []Device{
+		Device{
+			Name:             "Living Room",
+			ClientIdentifier: "roku-1",
+			PublicAddress:    connection,
+			Product:          "Plex for Roku",
+			Provides:         []string{"player"},
+			Connections:      []Connection{Connection{connection}},
+		},
+	}
+
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("\nExpected: %+v\n\nGot: %+v", expected, result)
+	}
+}
+
+func TestDiscoverNothing(t *testing.T) {
+	address, stop := startGDMResponder(t)
+	defer stop()
+	defer func(original string) { gdmServerAddress = original }(gdmServerAddress)
+	gdmServerAddress = address
+
+	result, err := DiscoverServers(100 * time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 0 {
+		t.Fatalf("Expected no servers, got %+v", result)
+	}
+}
+
+func TestDiscoverPlayersWithoutIdentifier(t *testing.T) {
+	resp := "HTTP/1.0 200 OK\r\n" +
+		"Content-Type: plex/media-player\r\n" +
+		"Name: %s\r\n" +
+		"Port: 32500\r\n\r\n"
+
+	address, stop := startGDMResponder(t, fmt.Sprintf(resp, "Kitchen"), fmt.Sprintf(resp, "Bedroom"))
+	defer stop()
+	defer func(original string) { gdmPlayerAddress = original }(gdmPlayerAddress)
+	gdmPlayerAddress = address
+
+	result, err := DiscoverPlayers(200 * time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result) != 2 || result[0].Name != "Kitchen" || result[1].Name != "Bedroom" {
+		t.Fatalf("Expected both players without identifiers, got %+v", result)
+	}
+}
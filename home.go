@@ -0,0 +1,96 @@
+package plex
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type homeUsersResp struct {
+	XMLName xml.Name   `xml:"MediaContainer"`
+	Users   []HomeUser `xml:"User"`
+}
+
+// HomeUser is a member of the account owner's Plex Home, either another plex.tv account or a managed user that
+// only exists inside the home
+type HomeUser struct {
+	ID                 int64     `xml:"id,attr"`
+	UUID               string    `xml:"uuid,attr"`
+	Title              string    `xml:"title,attr"`
+	Username           string    `xml:"username,attr"`
+	Email              string    `xml:"email,attr"`
+	Thumb              HTTPURL   `xml:"thumb,attr"`
+	Admin              IntAsBool `xml:"admin,attr"`
+	Guest              IntAsBool `xml:"guest,attr"`
+	Restricted         IntAsBool `xml:"restricted,attr"`
+	RestrictionProfile string    `xml:"restrictionProfile,attr"`
+	Protected          IntAsBool `xml:"protected,attr"`
+	HasPassword        IntAsBool `xml:"hasPassword,attr"`
+}
+
+// Restriction profiles for managed users
+const (
+	RestrictionLittleKid = "little_kid"
+	RestrictionOlderKid  = "older_kid"
+	RestrictionTeen      = "teen"
+)
+
+func (user User) GetHomeUsers() ([]HomeUser, error) {
+	resp := &homeUsersResp{}
+	if err := user.fetch("GET", "/api/home/users", nil, resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Users, nil
+}
+
+// SwitchHomeUser signs in as another member of the home. The returned User has that member's auth token, so
+// servers and activity fetched with it are attributed to them. Pin may be empty if the member has none.
+func (user User) SwitchHomeUser(id int64, pin string) (User, error) {
+	params := url.Values{}
+	if pin != "" {
+		params.Set("pin", pin)
+	}
+
+	req, err := user.newRequest("POST", "/api/home/users/"+strconv.FormatInt(id, 10)+"/switch", params, nil)
+	if err != nil {
+		return User{}, err
+	}
+
+	resp, err := fetchContent(req, http.StatusCreated, user.Retry)
+	if err != nil {
+		return User{}, err
+	}
+
+	switched := User{}
+	if err := xml.Unmarshal(resp, &switched); err != nil {
+		return User{}, err
+	}
+	switched.Retry = user.Retry
+
+	return switched, nil
+}
+
+// CreateManagedUser adds a user without a plex.tv account to the home. RestrictionProfile is one of the
+// Restriction constants, or empty for an unrestricted user.
+func (user User) CreateManagedUser(title, restrictionProfile string) (HomeUser, error) {
+	params := url.Values{}
+	params.Set("title", title)
+	if restrictionProfile != "" {
+		params.Set("restricted", "1")
+		params.Set("restrictionProfile", restrictionProfile)
+	}
+
+	created := HomeUser{}
+	if err := user.fetch("POST", "/api/home/users", params, &created); err != nil {
+		return HomeUser{}, err
+	}
+
+	return created, nil
+}
+
+// RemoveHomeUser removes a member from the home. Managed users are deleted entirely.
+func (user User) RemoveHomeUser(id int64) error {
+	return user.fetch("DELETE", "/api/home/users/"+strconv.FormatInt(id, 10), nil, nil)
+}
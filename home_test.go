@@ -0,0 +1,119 @@
+package plex
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestGetHomeUsersSuccess(t *testing.T) {
+	resp := `<?xml version="1.0" encoding="UTF-8"?>
+	<MediaContainer friendlyName="myPlex" identifier="com.plexapp.plugins.myplex" machineIdentifier="abc" size="2">
+	  <User id="1" uuid="uuid-1" admin="1" guest="0" restricted="0" restrictionProfile="" hasPassword="1" protected="1" title="Parent" username="parent" email="parent@address.com" thumb="https://plex.tv/users/uuid-1/avatar" />
+	  <User id="2" uuid="uuid-2" admin="0" guest="0" restricted="1" restrictionProfile="little_kid" hasPassword="0" protected="0" title="Kid" username="" email="" thumb="https://plex.tv/users/uuid-2/avatar" />
+	</MediaContainer>`
+
+	client = makeFakeClient(t, http.StatusOK, resp, makeServerRequest(t, "GET", "https://plex.tv/api/home/users"))
+
+	result, err := testOwner.GetHomeUsers()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []HomeUser{
+		HomeUser{
+			ID:          1,
+			UUID:        "uuid-1",
+			Title:       "Parent",
+			Username:    "parent",
+			Email:       "parent@address.com",
+			Thumb:       HTTPURL{url.URL{Scheme: "https", Host: "plex.tv", Path: "/users/uuid-1/avatar"}},
+			Admin:       true,
+			Protected:   true,
+			HasPassword: true,
+		},
+		HomeUser{
+			ID:                 2,
+			UUID:               "uuid-2",
+			Title:              "Kid",
+			Thumb:              HTTPURL{url.URL{Scheme: "https", Host: "plex.tv", Path: "/users/uuid-2/avatar"}},
+			Restricted:         true,
+			RestrictionProfile: RestrictionLittleKid,
+		},
+	}
+
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("\nExpected: %+v\n\nGot: %+v", expected, result)
+	}
+}
+
+func TestGetHomeUsersFail(t *testing.T) {
+	client = makeFakeClient(t, http.StatusUnauthorized, "", makeServerRequest(t, "GET", "https://plex.tv/api/home/users"))
+
+	if _, err := testOwner.GetHomeUsers(); err == nil {
+		t.Fatal("Should err when plex.tv returns 401")
+	}
+}
+
+func TestSwitchHomeUserSuccess(t *testing.T) {
+	resp := `<?xml version="1.0" encoding="UTF-8"?>
+	<user id="2" uuid="uuid-2" title="Kid" username="" email="" thumb="https://plex.tv/users/uuid-2/avatar" authenticationToken="kidToken" restricted="1" home="1">
+	  <subscription active="1" status="Active" plan="lifetime" />
+	</user>`
+
+	expectedReq := makeServerRequest(t, "POST", "https://plex.tv/api/home/users/2/switch?pin=1234")
+	client = makeFakeClient(t, http.StatusCreated, resp, expectedReq)
+
+	result, err := testOwner.SwitchHomeUser(2, "1234")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := User{
+		ID:           2,
+		Thumb:        HTTPURL{url.URL{Scheme: "https", Host: "plex.tv", Path: "/users/uuid-2/avatar"}},
+		Title:        "Kid",
+		AuthToken:    "kidToken",
+		Subscription: Subscription{Active: true, Plan: "lifetime"},
+	}
+
+	if result != expected {
+		t.Fatalf("\nExpected: %+v\nGot: %+v", expected, result)
+	}
+}
+
+func TestSwitchHomeUserFail(t *testing.T) {
+	expectedReq := makeServerRequest(t, "POST", "https://plex.tv/api/home/users/2/switch")
+	client = makeFakeClient(t, http.StatusUnauthorized, "", expectedReq)
+
+	if _, err := testOwner.SwitchHomeUser(2, ""); err == nil {
+		t.Fatal("Should err when the pin is wrong")
+	}
+}
+
+func TestCreateManagedUserSuccess(t *testing.T) {
+	resp := `<?xml version="1.0" encoding="UTF-8"?>
+	<User id="3" uuid="uuid-3" admin="0" guest="0" restricted="1" restrictionProfile="teen" title="Teen" />`
+
+	expectedReq := makeServerRequest(t, "POST", "https://plex.tv/api/home/users?restricted=1&restrictionProfile=teen&title=Teen")
+	client = makeFakeClient(t, http.StatusOK, resp, expectedReq)
+
+	result, err := testOwner.CreateManagedUser("Teen", RestrictionTeen)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := HomeUser{ID: 3, UUID: "uuid-3", Title: "Teen", Restricted: true, RestrictionProfile: RestrictionTeen}
+	if result != expected {
+		t.Fatalf("\nExpected: %+v\nGot: %+v", expected, result)
+	}
+}
+
+func TestRemoveHomeUserSuccess(t *testing.T) {
+	client = makeFakeClient(t, http.StatusOK, "", makeServerRequest(t, "DELETE", "https://plex.tv/api/home/users/3"))
+
+	if err := testOwner.RemoveHomeUser(3); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,68 @@
+package plex
+
+// Identity identifies a server. It can be fetched without an auth token.
+type Identity struct {
+	MachineIdentifier string    `xml:"machineIdentifier,attr" json:"machineIdentifier"`
+	Version           string    `xml:"version,attr" json:"version"`
+	Claimed           IntAsBool `xml:"claimed,attr" json:"claimed"`
+}
+
+// Capabilities describes what a server is and what it can do
+type Capabilities struct {
+	FriendlyName                  string              `xml:"friendlyName,attr" json:"friendlyName"`
+	MachineIdentifier             string              `xml:"machineIdentifier,attr" json:"machineIdentifier"`
+	Version                       string              `xml:"version,attr" json:"version"`
+	Platform                      string              `xml:"platform,attr" json:"platform"`
+	PlatformVersion               string              `xml:"platformVersion,attr" json:"platformVersion"`
+	MyPlex                        IntAsBool           `xml:"myPlex,attr" json:"myPlex"`
+	MyPlexUsername                string              `xml:"myPlexUsername,attr" json:"myPlexUsername"`
+	MyPlexSubscription            IntAsBool           `xml:"myPlexSubscription,attr" json:"myPlexSubscription"`
+	AllowSync                     IntAsBool           `xml:"allowSync,attr" json:"allowSync"`
+	AllowSharing                  IntAsBool           `xml:"allowSharing,attr" json:"allowSharing"`
+	AllowCameraUpload             IntAsBool           `xml:"allowCameraUpload,attr" json:"allowCameraUpload"`
+	AllowMediaDeletion            IntAsBool           `xml:"allowMediaDeletion,attr" json:"allowMediaDeletion"`
+	Multiuser                     IntAsBool           `xml:"multiuser,attr" json:"multiuser"`
+	TranscoderVideo               IntAsBool           `xml:"transcoderVideo,attr" json:"transcoderVideo"`
+	TranscoderAudio               IntAsBool           `xml:"transcoderAudio,attr" json:"transcoderAudio"`
+	TranscoderPhoto               IntAsBool           `xml:"transcoderPhoto,attr" json:"transcoderPhoto"`
+	TranscoderSubtitles           IntAsBool           `xml:"transcoderSubtitles,attr" json:"transcoderSubtitles"`
+	TranscoderActiveVideoSessions int                 `xml:"transcoderActiveVideoSessions,attr" json:"transcoderActiveVideoSessions"`
+	TranscoderVideoBitrates       CommaSeperatedSlice `xml:"transcoderVideoBitrates,attr" json:"transcoderVideoBitrates"`
+	TranscoderVideoResolutions    CommaSeperatedSlice `xml:"transcoderVideoResolutions,attr" json:"transcoderVideoResolutions"`
+	OwnerFeatures                 CommaSeperatedSlice `xml:"ownerFeatures,attr" json:"ownerFeatures"`
+	UpdatedAt                     UnixTime            `xml:"updatedAt,attr" json:"updatedAt"`
+}
+
+func (server Server) GetIdentity() (Identity, error) {
+	identity := Identity{}
+	if err := server.fetch("GET", "/identity", nil, &identity); err != nil {
+		return Identity{}, err
+	}
+
+	return identity, nil
+}
+
+func (server Server) GetCapabilities() (Capabilities, error) {
+	capabilities := Capabilities{}
+	if err := server.fetch("GET", "/", nil, &capabilities); err != nil {
+		return Capabilities{}, err
+	}
+
+	return capabilities, nil
+}
+
+// HasPlexPass is true when the server owner has a Plex Pass subscription
+func (capabilities Capabilities) HasPlexPass() bool {
+	return bool(capabilities.MyPlexSubscription)
+}
+
+// HasFeature reports whether the server owner's account has the named feature, e.g. webhooks or camera_upload.
+// Use it to check a call is available before making it.
+func (capabilities Capabilities) HasFeature(feature string) bool {
+	for _, ownerFeature := range capabilities.OwnerFeatures {
+		if ownerFeature == feature {
+			return true
+		}
+	}
+	return false
+}
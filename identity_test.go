@@ -0,0 +1,99 @@
+package plex
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetIdentitySuccess(t *testing.T) {
+	resp := `<?xml version="1.0" encoding="UTF-8"?>
+	<MediaContainer size="0" claimed="1" machineIdentifier="machineID" version="1.32.5.7349-8f4248874"></MediaContainer>`
+
+	client = makeFakeClient(t, http.StatusOK, resp, makeServerRequest(t, "GET", "http://server.com:4040/identity"))
+
+	result, err := makeTestServer().GetIdentity()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Identity{MachineIdentifier: "machineID", Version: "1.32.5.7349-8f4248874", Claimed: true}
+	if result != expected {
+		t.Fatalf("\nExpected: %+v\nGot: %+v", expected, result)
+	}
+}
+
+func TestGetIdentityJSONSuccess(t *testing.T) {
+	resp := `{"MediaContainer": {"size": 0, "claimed": true, "machineIdentifier": "machineID", "version": "1.32.5.7349-8f4248874"}}`
+
+	client = makeFakeClient(t, http.StatusOK, resp, makeJSONServerRequest(t, "GET", "http://server.com:4040/identity"))
+
+	result, err := makeJSONTestServer().GetIdentity()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Identity{MachineIdentifier: "machineID", Version: "1.32.5.7349-8f4248874", Claimed: true}
+	if result != expected {
+		t.Fatalf("\nExpected: %+v\nGot: %+v", expected, result)
+	}
+}
+
+func TestGetIdentityFail(t *testing.T) {
+	client = makeFakeClient(t, http.StatusServiceUnavailable, "", makeServerRequest(t, "GET", "http://server.com:4040/identity"))
+	_, restore := recordWaits()
+	defer restore()
+
+	if _, err := makeTestServer().GetIdentity(); err == nil {
+		t.Fatal("GetIdentity returned success when it received bad status code")
+	}
+}
+
+func TestGetCapabilitiesSuccess(t *testing.T) {
+	resp := `<?xml version="1.0" encoding="UTF-8"?>
+	<MediaContainer size="2" allowCameraUpload="1" allowChannelAccess="1" allowMediaDeletion="1" allowSharing="1" allowSync="1" backgroundProcessing="1" certificate="1" companionProxy="1" friendlyName="Server" machineIdentifier="machineID" multiuser="1" myPlex="1" myPlexMappingState="mapped" myPlexSigninState="ok" myPlexSubscription="1" myPlexUsername="username" ownerFeatures="camera_upload,webhooks,hardware_transcoding" platform="Linux" platformVersion="5.15" sync="1" transcoderActiveVideoSessions="1" transcoderAudio="1" transcoderLyrics="1" transcoderPhoto="1" transcoderSubtitles="1" transcoderVideo="1" transcoderVideoBitrates="64,96,208" transcoderVideoQualities="0,1,2" transcoderVideoResolutions="128,128,160" updatedAt="1430601269" version="1.32.5.7349-8f4248874">
+	  <Directory count="1" key="activities" title="activities" />
+	  <Directory count="1" key="library" title="library" />
+	</MediaContainer>`
+
+	client = makeFakeClient(t, http.StatusOK, resp, makeServerRequest(t, "GET", "http://server.com:4040/"))
+
+	result, err := makeTestServer().GetCapabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Capabilities{
+		FriendlyName:                  "Server",
+		MachineIdentifier:             "machineID",
+		Version:                       "1.32.5.7349-8f4248874",
+		Platform:                      "Linux",
+		PlatformVersion:               "5.15",
+		MyPlex:                        true,
+		MyPlexUsername:                "username",
+		MyPlexSubscription:            true,
+		AllowSync:                     true,
+		AllowSharing:                  true,
+		AllowCameraUpload:             true,
+		AllowMediaDeletion:            true,
+		Multiuser:                     true,
+		TranscoderVideo:               true,
+		TranscoderAudio:               true,
+		TranscoderPhoto:               true,
+		TranscoderSubtitles:           true,
+		TranscoderActiveVideoSessions: 1,
+		TranscoderVideoBitrates:       []string{"64", "96", "208"},
+		TranscoderVideoResolutions:    []string{"128", "128", "160"},
+		OwnerFeatures:                 []string{"camera_upload", "webhooks", "hardware_transcoding"},
+		UpdatedAt:                     UnixTime{time.Unix(1430601269, 0)},
+	}
+
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("\nExpected: %+v\n\nGot: %+v", expected, result)
+	}
+
+	if !result.HasPlexPass() || !result.HasFeature("webhooks") || result.HasFeature("livetv") {
+		t.Fatal("Plex Pass and feature flags did not match the response")
+	}
+}
@@ -0,0 +1,69 @@
+package plex
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ImageOptions control how the server transcodes an image. The zero value returns a JPEG scaled to fit inside
+// the requested size.
+type ImageOptions struct {
+	// Format is jpeg or png
+	Format string
+	// Blur is the radius of a blur applied to the image, useful for backgrounds
+	Blur int
+	// Desaturation is the percentage of color removed from the image, 100 is black and white
+	Desaturation int
+	// MinSize scales the image to cover the requested size instead of fitting inside it
+	MinSize bool
+	// Upscale allows images smaller than the requested size to be made larger
+	Upscale bool
+}
+
+// ImageURL returns a URL for the image at path, such as a Video's Thumb or Art, transcoded to width by height.
+// The URL includes the owner's token so it can be used directly, e.g. as the src of an img tag.
+func (server Server) ImageURL(path URLPath, width, height int, opts ImageOptions) HTTPURL {
+	params := imageParams(path, width, height, opts)
+	params.Set("X-Plex-Token", server.Owner.AuthToken)
+
+	address := server.PublicAddress
+	address.Path = "/photo/:/transcode"
+	address.RawQuery = params.Encode()
+	return address
+}
+
+// FetchImage returns the contents of the image at path transcoded to width by height
+func (server Server) FetchImage(ctx context.Context, path URLPath, width, height int, opts ImageOptions) ([]byte, error) {
+	req, err := server.newRequest("GET", "/photo/:/transcode", imageParams(path, width, height, opts), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Del("Accept")
+
+	return fetchContent(req.WithContext(ctx), http.StatusOK, server.Retry)
+}
+
+func imageParams(path URLPath, width, height int, opts ImageOptions) url.Values {
+	params := url.Values{}
+	params.Set("url", path.String())
+	params.Set("width", strconv.Itoa(width))
+	params.Set("height", strconv.Itoa(height))
+	if opts.Format != "" {
+		params.Set("format", opts.Format)
+	}
+	if opts.Blur > 0 {
+		params.Set("blur", strconv.Itoa(opts.Blur))
+	}
+	if opts.Desaturation > 0 {
+		params.Set("saturation", strconv.Itoa(100-opts.Desaturation))
+	}
+	if opts.MinSize {
+		params.Set("minSize", "1")
+	}
+	if opts.Upscale {
+		params.Set("upscale", "1")
+	}
+	return params
+}
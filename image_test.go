@@ -0,0 +1,44 @@
+package plex
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+var testPoster = URLPath{url.URL{Path: "/library/metadata/1751/thumb/1430373196"}}
+
+func TestImageURL(t *testing.T) {
+	opts := ImageOptions{Format: "png", Blur: 20, Desaturation: 100, MinSize: true, Upscale: true}
+	result := makeTestServer().ImageURL(testPoster, 300, 450, opts)
+
+	expected := "http://server.com:4040/photo/:/transcode?X-Plex-Token=authToken&blur=20&format=png&height=450&" +
+		"minSize=1&saturation=0&upscale=1&url=%2Flibrary%2Fmetadata%2F1751%2Fthumb%2F1430373196&width=300"
+	if result.String() != expected {
+		t.Fatalf("\nExpected: %s\n\nGot: %s", expected, result)
+	}
+}
+
+func TestFetchImageSuccess(t *testing.T) {
+	expectedURL := "http://server.com:4040/photo/:/transcode?height=450&url=%2Flibrary%2Fmetadata%2F1751%2Fthumb%2F1430373196&width=300"
+	client = makeFakeClient(t, http.StatusOK, "jpeg data", makeServerRequest(t, "GET", expectedURL))
+
+	result, err := makeTestServer().FetchImage(context.Background(), testPoster, 300, 450, ImageOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(result) != "jpeg data" {
+		t.Fatalf("Unexpected image %q", result)
+	}
+}
+
+func TestFetchImageFail(t *testing.T) {
+	expectedURL := "http://server.com:4040/photo/:/transcode?height=450&url=%2Flibrary%2Fmetadata%2F1751%2Fthumb%2F1430373196&width=300"
+	client = makeFakeClient(t, http.StatusNotFound, "", makeServerRequest(t, "GET", expectedURL))
+
+	if _, err := makeTestServer().FetchImage(context.Background(), testPoster, 300, 450, ImageOptions{}); err == nil {
+		t.Fatal("FetchImage returned success when it received bad status code")
+	}
+}
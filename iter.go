@@ -0,0 +1,184 @@
+package plex
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Number of items requested from the server at a time. Hook to override for tests
+var iterPageSize = 500
+
+// SectionIterator walks the items of a section one at a time. Pages of items are requested from the server as
+// they are needed and decoded as they stream in, so memory use does not grow with the size of the library.
+//
+//	it := section.Iter(ctx)
+//	defer it.Close()
+//	for it.Next() {
+//		video := it.Video()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type SectionIterator struct {
+	ctx      context.Context
+	section  Section
+	params   url.Values
+	start    int
+	total    int
+	pageSize int
+	pageRead int
+	body     io.ReadCloser
+	decoder  *xml.Decoder
+	video    Video
+	err      error
+	done     bool
+}
+
+// Iter returns an iterator over the top level items of the section, e.g. the movies of a movie section or the
+// shows of a show section
+func (section Section) Iter(ctx context.Context) *SectionIterator {
+	return section.iter(ctx, url.Values{})
+}
+
+// IterType returns an iterator over every item of the given type in the section, e.g. the episodes of a show
+// section or the tracks of a music section
+func (section Section) IterType(ctx context.Context, itemType string) *SectionIterator {
+	metadataType, err := lookupMetadataType(itemType)
+	if err != nil {
+		return &SectionIterator{err: err}
+	}
+
+	params := url.Values{}
+	params.Set("type", strconv.Itoa(metadataType))
+	return section.iter(ctx, params)
+}
+
+func (section Section) iter(ctx context.Context, params url.Values) *SectionIterator {
+	return &SectionIterator{
+		ctx:      ctx,
+		section:  section,
+		params:   params,
+		total:    -1,
+		pageSize: iterPageSize,
+	}
+}
+
+// Next advances to the next item, returning false when there are no more items or an error occurred
+func (it *SectionIterator) Next() bool {
+	for it.err == nil && !it.done {
+		if it.decoder == nil {
+			if it.total >= 0 && it.start >= it.total {
+				it.done = true
+				break
+			}
+			it.err = it.openPage()
+			continue
+		}
+
+		token, err := it.decoder.Token()
+		if err == io.EOF {
+			// A short page means the server has run out of items, even if it didn't send a total
+			if it.pageRead < it.pageSize {
+				it.done = true
+			}
+			it.closePage()
+			continue
+		}
+		if err != nil {
+			it.err = err
+			break
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		if start.Name.Local == "MediaContainer" {
+			it.err = it.readContainer(start)
+			continue
+		}
+
+		it.video = Video{}
+		if err := it.decoder.DecodeElement(&it.video, &start); err != nil {
+			it.err = err
+			break
+		}
+		it.video.Server = it.section.Server
+		it.start++
+		it.pageRead++
+		return true
+	}
+
+	it.Close()
+	return false
+}
+
+// Video returns the item Next advanced to
+func (it *SectionIterator) Video() Video {
+	return it.video
+}
+
+// Err returns the error that stopped iteration, if any
+func (it *SectionIterator) Err() error {
+	return it.err
+}
+
+// Close stops iteration early and releases the connection to the server. It is safe to call more than once.
+func (it *SectionIterator) Close() error {
+	it.done = true
+	return it.closePage()
+}
+
+func (it *SectionIterator) openPage() error {
+	params := url.Values{}
+	for key, values := range it.params {
+		params[key] = values
+	}
+	params.Set("X-Plex-Container-Start", strconv.Itoa(it.start))
+	params.Set("X-Plex-Container-Size", strconv.Itoa(it.pageSize))
+
+	req, err := it.section.Server.newRequest("GET", "/library/sections/"+it.section.Key+"/all", params, nil)
+	if err != nil {
+		return err
+	}
+	// Streaming only works with XML, whatever the server's Format is
+	req.Header.Del("Accept")
+
+	resp, err := doRequest(req.WithContext(it.ctx), http.StatusOK, it.section.Server.Retry)
+	if err != nil {
+		return err
+	}
+
+	it.body = resp.Body
+	it.decoder = xml.NewDecoder(resp.Body)
+	it.pageRead = 0
+	return nil
+}
+
+func (it *SectionIterator) readContainer(start xml.StartElement) error {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "totalSize" {
+			total, err := strconv.Atoi(attr.Value)
+			if err != nil {
+				return err
+			}
+			it.total = total
+		}
+	}
+	return nil
+}
+
+func (it *SectionIterator) closePage() error {
+	it.decoder = nil
+	if it.body == nil {
+		return nil
+	}
+
+	err := it.body.Close()
+	it.body = nil
+	return err
+}
@@ -0,0 +1,138 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// startLibraryServer serves a section with the given number of items at /library/sections/1/all
+func startLibraryServer(t *testing.T, items int, sendTotal bool) (*httptest.Server, Section) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/library/sections/1/all" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("X-Plex-Token") != "authToken" || r.Header.Get("Accept") != "" {
+			t.Errorf("Unexpected headers %v", r.Header)
+		}
+
+		start, _ := strconv.Atoi(r.URL.Query().Get("X-Plex-Container-Start"))
+		size, _ := strconv.Atoi(r.URL.Query().Get("X-Plex-Container-Size"))
+		end := start + size
+		if end > items {
+			end = items
+		}
+
+		total := ""
+		if sendTotal {
+			total = fmt.Sprintf(` totalSize="%d"`, items)
+		}
+		fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><MediaContainer size="%d" offset="%d"%s>`, end-start, start, total)
+		for i := start; i < end; i++ {
+			fmt.Fprintf(w, `<Track ratingKey="%d" type="track" title="Track %d"><Media audioCodec="flac" /></Track>`, i, i)
+		}
+		fmt.Fprint(w, `</MediaContainer>`)
+	}
+
+	testServer, server := startTestServer(t, handler)
+	return testServer, Section{Key: "1", Type: "artist", Server: server}
+}
+
+func TestSectionIter(t *testing.T) {
+	iterPageSize = 2
+	defer func() { iterPageSize = 500 }()
+
+	for _, sendTotal := range []bool{true, false} {
+		testServer, section := startLibraryServer(t, 5, sendTotal)
+
+		it := section.Iter(context.Background())
+		count := 0
+		for it.Next() {
+			video := it.Video()
+			if video.RatingKey != strconv.Itoa(count) || video.Media.AudioCodec != "flac" {
+				t.Fatalf("Unexpected item %d: %+v", count, video)
+			}
+			count++
+		}
+		if err := it.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if count != 5 {
+			t.Fatalf("Expected 5 items, got %d", count)
+		}
+
+		testServer.Close()
+	}
+}
+
+func TestSectionIterEmpty(t *testing.T) {
+	testServer, section := startLibraryServer(t, 0, true)
+	defer testServer.Close()
+
+	it := section.Iter(context.Background())
+	if it.Next() {
+		t.Fatalf("Unexpected item %+v", it.Video())
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSectionIterType(t *testing.T) {
+	testServer, server := startTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("type") != "10" {
+			t.Errorf("Unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `<MediaContainer totalSize="0"></MediaContainer>`)
+	})
+	defer testServer.Close()
+	section := Section{Key: "1", Server: server}
+
+	it := section.IterType(context.Background(), "track")
+	for it.Next() {
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	it = section.IterType(context.Background(), "song")
+	if it.Next() || it.Err() == nil {
+		t.Fatal("Should err when the item type is unknown")
+	}
+}
+
+func TestSectionIterCancel(t *testing.T) {
+	iterPageSize = 2
+	defer func() { iterPageSize = 500 }()
+
+	testServer, section := startLibraryServer(t, 5, true)
+	defer testServer.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	it := section.Iter(ctx)
+	for it.Next() {
+		if it.Video().RatingKey == "1" {
+			cancel()
+		}
+	}
+	if it.Err() == nil {
+		t.Fatal("Should err when the context is canceled")
+	}
+}
+
+func TestSectionIterFail(t *testing.T) {
+	testServer, server := startTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	defer testServer.Close()
+	section := Section{Key: "1", Server: server}
+
+	it := section.Iter(context.Background())
+	if it.Next() || it.Err() == nil {
+		t.Fatal("Should err when server returns 401")
+	}
+}
@@ -0,0 +1,142 @@
+package plex
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+)
+
+type sectionsResp struct {
+	XMLName  xml.Name  `xml:"MediaContainer" json:"-"`
+	Sections []Section `xml:"Directory" json:"Directory"`
+}
+
+type Section struct {
+	Key        string     `xml:"key,attr" json:"key"`
+	UUID       string     `xml:"uuid,attr" json:"uuid"`
+	Type       string     `xml:"type,attr" json:"type"`
+	Title      string     `xml:"title,attr" json:"title"`
+	Agent      string     `xml:"agent,attr" json:"agent"`
+	Scanner    string     `xml:"scanner,attr" json:"scanner"`
+	Language   string     `xml:"language,attr" json:"language"`
+	Refreshing IntAsBool  `xml:"refreshing,attr" json:"refreshing"`
+	CreatedAt  UnixTime   `xml:"createdAt,attr" json:"createdAt"`
+	UpdatedAt  UnixTime   `xml:"updatedAt,attr" json:"updatedAt"`
+	ScannedAt  UnixTime   `xml:"scannedAt,attr" json:"scannedAt"`
+	Locations  []Location `xml:"Location" json:"Location"`
+	Server     Server     `xml:"-" json:"-"`
+}
+
+type sectionSizeResp struct {
+	XMLName   xml.Name `xml:"MediaContainer" json:"-"`
+	TotalSize int      `xml:"totalSize,attr" json:"totalSize"`
+}
+
+type Location struct {
+	ID   int    `xml:"id,attr" json:"id"`
+	Path string `xml:"path,attr" json:"path"`
+}
+
+// The numeric metadata types the server uses when a type has to be passed as a parameter
+var metadataTypes = map[string]int{
+	"movie":   1,
+	"show":    2,
+	"season":  3,
+	"episode": 4,
+	"artist":  8,
+	"album":   9,
+	"track":   10,
+	"photo":   13,
+}
+
+func (server Server) GetSections() ([]Section, error) {
+	return server.GetSectionsContext(context.Background())
+}
+
+// GetSectionsContext is GetSections with a context to cancel the request or give it a deadline
+func (server Server) GetSectionsContext(ctx context.Context) ([]Section, error) {
+	container := &sectionsResp{}
+	if err := server.fetchContext(ctx, "GET", "/library/sections", nil, container); err != nil {
+		return nil, err
+	}
+
+	for i := range container.Sections {
+		container.Sections[i].Server = server
+	}
+
+	return container.Sections, nil
+}
+
+func lookupMetadataType(itemType string) (int, error) {
+	metadataType, ok := metadataTypes[itemType]
+	if !ok {
+		return 0, fmt.Errorf("Unknown metadata type %s", itemType)
+	}
+	return metadataType, nil
+}
+
+// libraryURI builds the server:// URI the server expects when metadata is referenced as a parameter
+func (server Server) libraryURI(path string) string {
+	return "server://" + server.ClientIdentifier + "/com.plexapp.plugins.library" + path
+}
+
+// GetSize returns the number of top level items in the section, e.g. movies or shows
+func (section Section) GetSize(ctx context.Context) (int, error) {
+	params := url.Values{}
+	params.Set("X-Plex-Container-Start", "0")
+	params.Set("X-Plex-Container-Size", "0")
+
+	container := &sectionSizeResp{}
+	if err := section.Server.fetchContext(ctx, "GET", "/library/sections/"+section.Key+"/all", params, container); err != nil {
+		return 0, err
+	}
+
+	return container.TotalSize, nil
+}
+
+// Scan looks for new, changed and removed files in all of the section's locations. Force rescans files that
+// haven't changed.
+func (section Section) Scan(force bool) error {
+	params := url.Values{}
+	if force {
+		params.Set("force", "1")
+	}
+
+	return section.Server.fetch("GET", "/library/sections/"+section.Key+"/refresh", params, nil)
+}
+
+// Refresh scans only the given path, which must be inside one of the section's locations
+func (section Section) Refresh(path string) error {
+	params := url.Values{}
+	params.Set("path", path)
+
+	return section.Server.fetch("GET", "/library/sections/"+section.Key+"/refresh", params, nil)
+}
+
+// WaitForScan blocks until the server has finished scanning the section
+func (section Section) WaitForScan(ctx context.Context) error {
+	return section.Server.WaitForActivities(ctx, ActivitiesForSection(section.Key))
+}
+
+// EmptyTrash removes the items whose files have been deleted from the section
+func (section Section) EmptyTrash() error {
+	return section.Server.fetch("PUT", "/library/sections/"+section.Key+"/emptyTrash", nil, nil)
+}
+
+// RefreshMetadata downloads fresh metadata for the item with the given rating key
+func (server Server) RefreshMetadata(ratingKey string) error {
+	return server.fetch("PUT", "/library/metadata/"+ratingKey+"/refresh", nil, nil)
+}
+
+// CleanBundles deletes metadata bundles that no longer belong to any item
+func (server Server) CleanBundles() error {
+	return server.fetch("PUT", "/library/clean/bundles", nil, nil)
+}
+
+func (server Server) OptimizeDatabase() error {
+	params := url.Values{}
+	params.Set("async", "1")
+
+	return server.fetch("PUT", "/library/optimize", params, nil)
+}
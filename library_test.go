@@ -0,0 +1,189 @@
+package plex
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetSectionsSuccess(t *testing.T) {
+	resp := `<?xml version="1.0" encoding="UTF-8"?>
+	<MediaContainer size="2" allowSync="0" identifier="com.plexapp.plugins.library" mediaTagPrefix="/system/bundle/media/flags/" mediaTagVersion="1430373196" title1="Plex Library">
+	  <Directory allowSync="0" art="/:/resources/movie-fanart.jpg" composite="/library/sections/1/composite/1430373196" filters="1" refreshing="0" thumb="/:/resources/movie.png" key="1" type="movie" title="Movies" agent="com.plexapp.agents.imdb" scanner="Plex Movie Scanner" language="en" uuid="uuid-1" updatedAt="1430373196" createdAt="1394924489" scannedAt="1430373196">
+	    <Location id="1" path="/media/Media/Movies" />
+	  </Directory>
+	  <Directory allowSync="0" refreshing="1" key="2" type="show" title="TV Shows" agent="com.plexapp.agents.thetvdb" scanner="Plex Series Scanner" language="en" uuid="uuid-2" updatedAt="1430373196" createdAt="1394924489" scannedAt="1430373196">
+	    <Location id="2" path="/media/Media/TV" />
+	    <Location id="3" path="/media/Media/More TV" />
+	  </Directory>
+	</MediaContainer>`
+
+	client = makeFakeClient(t, http.StatusOK, resp, makeServerRequest(t, "GET", "http://server.com:4040/library/sections"))
+
+	server := makeTestServer()
+	result, err := server.GetSections()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Section{
+		Section{
+			Key:       "1",
+			UUID:      "uuid-1",
+			Type:      "movie",
+			Title:     "Movies",
+			Agent:     "com.plexapp.agents.imdb",
+			Scanner:   "Plex Movie Scanner",
+			Language:  "en",
+			CreatedAt: UnixTime{time.Unix(1394924489, 0)},
+			UpdatedAt: UnixTime{time.Unix(1430373196, 0)},
+			ScannedAt: UnixTime{time.Unix(1430373196, 0)},
+			Locations: []Location{Location{ID: 1, Path: "/media/Media/Movies"}},
+			Server:    server,
+		},
+		Section{
+			Key:        "2",
+			UUID:       "uuid-2",
+			Type:       "show",
+			Title:      "TV Shows",
+			Agent:      "com.plexapp.agents.thetvdb",
+			Scanner:    "Plex Series Scanner",
+			Language:   "en",
+			Refreshing: true,
+			CreatedAt:  UnixTime{time.Unix(1394924489, 0)},
+			UpdatedAt:  UnixTime{time.Unix(1430373196, 0)},
+			ScannedAt:  UnixTime{time.Unix(1430373196, 0)},
+			Locations: []Location{
+				Location{ID: 2, Path: "/media/Media/TV"},
+				Location{ID: 3, Path: "/media/Media/More TV"},
+			},
+			Server: server,
+		},
+	}
+
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("\nExpected: %+v\n\nGot: %+v", expected, result)
+	}
+}
+
+func TestGetSectionsFail(t *testing.T) {
+	client = makeFakeClient(t, http.StatusUnauthorized, "", makeServerRequest(t, "GET", "http://server.com:4040/library/sections"))
+
+	if _, err := makeTestServer().GetSections(); err == nil {
+		t.Fatal("GetSections returned success when it received bad status code")
+	}
+}
+
+func TestLibraryURI(t *testing.T) {
+	server := makeTestServer()
+	server.PublicAddress = HTTPURL{url.URL{Scheme: "http", Host: "other.com"}}
+
+	uri := server.libraryURI("/library/metadata/1")
+	if uri != "server://machineID/com.plexapp.plugins.library/library/metadata/1" {
+		t.Fatalf("Unexpected uri %s", uri)
+	}
+}
+
+func TestGetSectionsJSONSuccess(t *testing.T) {
+	resp := `{"MediaContainer": {"size": 1, "title1": "Plex Library", "Directory": [{
+		"allowSync": false, "refreshing": false, "key": "1", "type": "movie", "title": "Movies",
+		"agent": "com.plexapp.agents.imdb", "scanner": "Plex Movie Scanner", "language": "en", "uuid": "uuid-1",
+		"updatedAt": 1430373196, "createdAt": 1394924489, "scannedAt": 1430373196,
+		"Location": [{"id": 1, "path": "/media/Media/Movies"}]
+	}]}}`
+
+	expectedReq := makeJSONServerRequest(t, "GET", "http://server.com:4040/library/sections")
+	client = makeFakeClient(t, http.StatusOK, resp, expectedReq)
+
+	server := makeJSONTestServer()
+	result, err := server.GetSections()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Section{
+		Section{
+			Key:       "1",
+			UUID:      "uuid-1",
+			Type:      "movie",
+			Title:     "Movies",
+			Agent:     "com.plexapp.agents.imdb",
+			Scanner:   "Plex Movie Scanner",
+			Language:  "en",
+			CreatedAt: UnixTime{time.Unix(1394924489, 0)},
+			UpdatedAt: UnixTime{time.Unix(1430373196, 0)},
+			ScannedAt: UnixTime{time.Unix(1430373196, 0)},
+			Locations: []Location{Location{ID: 1, Path: "/media/Media/Movies"}},
+			Server:    server,
+		},
+	}
+
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("\nExpected: %+v\n\nGot: %+v", expected, result)
+	}
+}
+
+func TestGetSectionsJSONMissingContainer(t *testing.T) {
+	expectedReq := makeJSONServerRequest(t, "GET", "http://server.com:4040/library/sections")
+	client = makeFakeClient(t, http.StatusOK, `{"errors": []}`, expectedReq)
+
+	if _, err := makeJSONTestServer().GetSections(); err == nil {
+		t.Fatal("Should err when the response has no MediaContainer")
+	}
+}
+
+func TestLibraryOperationsSuccess(t *testing.T) {
+	section := Section{Key: "1", Server: makeTestServer()}
+
+	tests := []struct {
+		name   string
+		method string
+		rawurl string
+		call   func() error
+	}{
+		{"Scan", "GET", "http://server.com:4040/library/sections/1/refresh", func() error { return section.Scan(false) }},
+		{"ForceScan", "GET", "http://server.com:4040/library/sections/1/refresh?force=1", func() error { return section.Scan(true) }},
+		{"Refresh", "GET", "http://server.com:4040/library/sections/1/refresh?path=%2Fmedia%2FMovies%2FNew+Movie",
+			func() error { return section.Refresh("/media/Movies/New Movie") }},
+		{"EmptyTrash", "PUT", "http://server.com:4040/library/sections/1/emptyTrash", section.EmptyTrash},
+		{"RefreshMetadata", "PUT", "http://server.com:4040/library/metadata/1751/refresh",
+			func() error { return makeTestServer().RefreshMetadata("1751") }},
+		{"CleanBundles", "PUT", "http://server.com:4040/library/clean/bundles", makeTestServer().CleanBundles},
+		{"OptimizeDatabase", "PUT", "http://server.com:4040/library/optimize?async=1", makeTestServer().OptimizeDatabase},
+	}
+
+	for _, test := range tests {
+		client = makeFakeClient(t, http.StatusOK, "", makeServerRequest(t, test.method, test.rawurl))
+		if err := test.call(); err != nil {
+			t.Errorf("%s: %s", test.name, err)
+		}
+	}
+}
+
+func TestScanFail(t *testing.T) {
+	client = makeFakeClient(t, http.StatusNotFound, "", makeServerRequest(t, "GET", "http://server.com:4040/library/sections/9/refresh"))
+
+	section := Section{Key: "9", Server: makeTestServer()}
+	if err := section.Scan(false); err == nil {
+		t.Fatal("Scan returned success when it received bad status code")
+	}
+}
+
+func TestGetSectionSizeSuccess(t *testing.T) {
+	resp := `<MediaContainer size="0" totalSize="1204" librarySectionTitle="Movies"></MediaContainer>`
+	expectedURL := "http://server.com:4040/library/sections/1/all?X-Plex-Container-Size=0&X-Plex-Container-Start=0"
+	client = makeFakeClient(t, http.StatusOK, resp, makeServerRequest(t, "GET", expectedURL))
+
+	section := Section{Key: "1", Server: makeTestServer()}
+	size, err := section.GetSize(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if size != 1204 {
+		t.Fatalf("Expected 1204 items, got %d", size)
+	}
+}
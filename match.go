@@ -0,0 +1,76 @@
+package plex
+
+import (
+	"encoding/xml"
+	"net/url"
+	"strconv"
+)
+
+type searchResultsResp struct {
+	XMLName xml.Name       `xml:"MediaContainer" json:"-"`
+	Results []SearchResult `xml:"SearchResult" json:"SearchResult"`
+}
+
+// SearchResult is a candidate an agent found for an item. Score is how confident the agent is, out of 100.
+type SearchResult struct {
+	GUID    string    `xml:"guid,attr" json:"guid"`
+	Name    string    `xml:"name,attr" json:"name"`
+	Year    int       `xml:"year,attr" json:"year"`
+	Score   int       `xml:"score,attr" json:"score"`
+	Thumb   HTTPURL   `xml:"thumb,attr" json:"thumb"`
+	Matched IntAsBool `xml:"matched,attr" json:"matched"`
+}
+
+// MatchHints narrow the search for matches. Empty fields are left for the server to fill in from the item.
+type MatchHints struct {
+	Title    string
+	Year     int
+	Agent    string
+	Language string
+}
+
+// Matches searches the item's agent for what the item could be, best match first
+func (server Server) Matches(ratingKey string, hints MatchHints) ([]SearchResult, error) {
+	params := url.Values{}
+	params.Set("manual", "1")
+	if hints.Title != "" {
+		params.Set("title", hints.Title)
+	}
+	if hints.Year != 0 {
+		params.Set("year", strconv.Itoa(hints.Year))
+	}
+	if hints.Agent != "" {
+		params.Set("agent", hints.Agent)
+	}
+	if hints.Language != "" {
+		params.Set("language", hints.Language)
+	}
+
+	container := &searchResultsResp{}
+	if err := server.fetch("GET", "/library/metadata/"+ratingKey+"/matches", params, container); err != nil {
+		return nil, err
+	}
+
+	return container.Results, nil
+}
+
+// FixMatch matches the item to guid, usually the GUID of one of its Matches, and returns the updated item
+func (server Server) FixMatch(ratingKey, guid string) (Video, error) {
+	params := url.Values{}
+	params.Set("guid", guid)
+
+	if err := server.fetch("PUT", "/library/metadata/"+ratingKey+"/match", params, nil); err != nil {
+		return Video{}, err
+	}
+
+	return server.GetMetadata(ratingKey)
+}
+
+// Unmatch removes the item's match and metadata and returns the updated item
+func (server Server) Unmatch(ratingKey string) (Video, error) {
+	if err := server.fetch("PUT", "/library/metadata/"+ratingKey+"/unmatch", nil, nil); err != nil {
+		return Video{}, err
+	}
+
+	return server.GetMetadata(ratingKey)
+}
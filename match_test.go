@@ -0,0 +1,165 @@
+package plex
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestMatchesSuccess(t *testing.T) {
+	resp := `<?xml version="1.0" encoding="UTF-8"?>
+	<MediaContainer size="2" identifier="com.plexapp.plugins.library" mediaTagPrefix="/system/bundle/media/flags/" mediaTagVersion="1430373196">
+	  <SearchResult type="movie" guid="com.plexapp.agents.imdb://tt0133093?lang=en" name="The Matrix" year="1999" score="100" thumb="https://image.tmdb.org/t/p/original/poster.jpg" matched="1" />
+	  <SearchResult type="movie" guid="com.plexapp.agents.imdb://tt0234215?lang=en" name="The Matrix Reloaded" year="2003" score="81" thumb="" matched="0" />
+	</MediaContainer>`
+
+	expectedURL := "http://server.com:4040/library/metadata/1751/matches?manual=1&title=The+Matrix&year=1999"
+	client = makeFakeClient(t, http.StatusOK, resp, makeServerRequest(t, "GET", expectedURL))
+
+	result, err := makeTestServer().Matches("1751", MatchHints{Title: "The Matrix", Year: 1999})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []SearchResult{
+		SearchResult{
+			GUID:    "com.plexapp.agents.imdb://tt0133093?lang=en",
+			Name:    "The Matrix",
+			Year:    1999,
+			Score:   100,
+			Thumb:   HTTPURL{url.URL{Scheme: "https", Host: "image.tmdb.org", Path: "/t/p/original/poster.jpg"}},
+			Matched: true,
+		},
+		SearchResult{
+			GUID:  "com.plexapp.agents.imdb://tt0234215?lang=en",
+			Name:  "The Matrix Reloaded",
+			Year:  2003,
+			Score: 81,
+		},
+	}
+
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("\nExpected: %+v\n\nGot: %+v", expected, result)
+	}
+}
+
+func TestMatchesJSONSuccess(t *testing.T) {
+	resp := `{"MediaContainer": {"size": 1, "identifier": "com.plexapp.plugins.library", "SearchResult": [
+	  {"type": "movie", "guid": "com.plexapp.agents.imdb://tt0133093?lang=en", "name": "The Matrix", "year": 1999, "score": 100, "thumb": "https://image.tmdb.org/t/p/original/poster.jpg", "matched": true}
+	]}}`
+
+	expectedURL := "http://server.com:4040/library/metadata/1751/matches?manual=1&title=The+Matrix"
+	client = makeFakeClient(t, http.StatusOK, resp, makeJSONServerRequest(t, "GET", expectedURL))
+
+	result, err := makeJSONTestServer().Matches("1751", MatchHints{Title: "The Matrix"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []SearchResult{
+		SearchResult{
+			GUID:    "com.plexapp.agents.imdb://tt0133093?lang=en",
+			Name:    "The Matrix",
+			Year:    1999,
+			Score:   100,
+			Thumb:   HTTPURL{url.URL{Scheme: "https", Host: "image.tmdb.org", Path: "/t/p/original/poster.jpg"}},
+			Matched: true,
+		},
+	}
+
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("\nExpected: %+v\n\nGot: %+v", expected, result)
+	}
+}
+
+func TestMatchesFail(t *testing.T) {
+	client = makeFakeClient(t, http.StatusNotFound, "", makeServerRequest(t, "GET", "http://server.com:4040/library/metadata/1751/matches?manual=1"))
+
+	if _, err := makeTestServer().Matches("1751", MatchHints{}); err == nil {
+		t.Fatal("Matches returned success when it received bad status code")
+	}
+}
+
+// startMetadataServer serves the item 1751 and records the requests made to it
+func startMetadataServer(t *testing.T) (*httptest.Server, Server, *[]string) {
+	var requests []string
+	testServer, server := startTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+		if r.Method == "GET" && r.URL.Path == "/library/metadata/1751" {
+			w.Write([]byte(`<MediaContainer size="1"><Video ratingKey="1751" key="/library/metadata/1751" type="movie" title="The Matrix" guid="com.plexapp.agents.imdb://tt0133093?lang=en" /></MediaContainer>`))
+		}
+	})
+	return testServer, server, &requests
+}
+
+func TestFixMatch(t *testing.T) {
+	testServer, server, requests := startMetadataServer(t)
+	defer testServer.Close()
+
+	result, err := server.FixMatch("1751", "com.plexapp.agents.imdb://tt0133093?lang=en")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedRequests := []string{
+		"PUT /library/metadata/1751/match?guid=com.plexapp.agents.imdb%3A%2F%2Ftt0133093%3Flang%3Den",
+		"GET /library/metadata/1751",
+	}
+	if !reflect.DeepEqual(expectedRequests, *requests) {
+		t.Fatalf("\nExpected: %v\n\nGot: %v", expectedRequests, *requests)
+	}
+
+	if result.Title != "The Matrix" || result.GUID != "com.plexapp.agents.imdb://tt0133093?lang=en" {
+		t.Fatalf("Unexpected item %+v", result)
+	}
+}
+
+func TestUnmatch(t *testing.T) {
+	testServer, server, requests := startMetadataServer(t)
+	defer testServer.Close()
+
+	if _, err := server.Unmatch("1751"); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedRequests := []string{"PUT /library/metadata/1751/unmatch", "GET /library/metadata/1751"}
+	if !reflect.DeepEqual(expectedRequests, *requests) {
+		t.Fatalf("\nExpected: %v\n\nGot: %v", expectedRequests, *requests)
+	}
+}
+
+func TestGetMetadataJSONSuccess(t *testing.T) {
+	// Library responses send the section id as a number, unlike sessions which quote it
+	resp := `{"MediaContainer": {"size": 1, "librarySectionID": 1, "Metadata": [
+	  {"ratingKey": "1751", "key": "/library/metadata/1751", "type": "movie", "title": "The Matrix", "librarySectionID": 1}
+	]}}`
+	client = makeFakeClient(t, http.StatusOK, resp, makeJSONServerRequest(t, "GET", "http://server.com:4040/library/metadata/1751"))
+
+	server := makeJSONTestServer()
+	result, err := server.GetMetadata("1751")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Video{
+		RatingKey:        "1751",
+		Key:              "/library/metadata/1751",
+		Type:             "movie",
+		Title:            "The Matrix",
+		LibrarySectionID: "1",
+		Server:           server,
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("\nExpected: %+v\n\nGot: %+v", expected, result)
+	}
+}
+
+func TestGetMetadataMissing(t *testing.T) {
+	client = makeFakeClient(t, http.StatusOK, `<MediaContainer size="0"></MediaContainer>`, makeServerRequest(t, "GET", "http://server.com:4040/library/metadata/1"))
+
+	if _, err := makeTestServer().GetMetadata("1"); err == nil {
+		t.Fatal("Should err when the server returns no item")
+	}
+}
@@ -0,0 +1,168 @@
+package plex
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// MetadataField is an editable field of an item's metadata
+type MetadataField string
+
+const (
+	FieldTitle                 MetadataField = "title"
+	FieldSortTitle             MetadataField = "titleSort"
+	FieldOriginalTitle         MetadataField = "originalTitle"
+	FieldSummary               MetadataField = "summary"
+	FieldTagline               MetadataField = "tagline"
+	FieldContentRating         MetadataField = "contentRating"
+	FieldStudio                MetadataField = "studio"
+	FieldYear                  MetadataField = "year"
+	FieldOriginallyAvailableAt MetadataField = "originallyAvailableAt"
+)
+
+// MetadataTag is a kind of tag that can be attached to an item
+type MetadataTag string
+
+const (
+	TagGenre      MetadataTag = "genre"
+	TagLabel      MetadataTag = "label"
+	TagCollection MetadataTag = "collection"
+	TagDirector   MetadataTag = "director"
+	TagWriter     MetadataTag = "writer"
+)
+
+// MetadataEdit collects changes to apply to an item with EditMetadata. The zero value is an edit that changes
+// nothing. Changed fields and tags are locked so the agent doesn't overwrite them on the next refresh.
+type MetadataEdit struct {
+	params  url.Values
+	tagsSet map[MetadataTag]int
+}
+
+// Set changes the value of field and locks it
+func (edit *MetadataEdit) Set(field MetadataField, value string) *MetadataEdit {
+	edit.values().Set(string(field)+".value", value)
+	return edit.Lock(field)
+}
+
+// Lock stops the agent from changing field without changing its value
+func (edit *MetadataEdit) Lock(field MetadataField) *MetadataEdit {
+	edit.values().Set(string(field)+".locked", "1")
+	return edit
+}
+
+// Unlock lets the agent change field again on the next refresh
+func (edit *MetadataEdit) Unlock(field MetadataField) *MetadataEdit {
+	edit.values().Set(string(field)+".locked", "0")
+	return edit
+}
+
+// AddTags attaches tags of the given kind to the item and locks them
+func (edit *MetadataEdit) AddTags(kind MetadataTag, tags ...string) *MetadataEdit {
+	params := edit.values()
+	for _, tag := range tags {
+		params.Set(string(kind)+"["+strconv.Itoa(edit.tagsSet[kind])+"].tag.tag", tag)
+		edit.tagsSet[kind]++
+	}
+	return edit.LockTags(kind)
+}
+
+// RemoveTags detaches tags of the given kind from the item and locks them
+func (edit *MetadataEdit) RemoveTags(kind MetadataTag, tags ...string) *MetadataEdit {
+	// The server splits the list on commas, so each tag is escaped to keep commas within a tag
+	escaped := make([]string, len(tags))
+	for i, tag := range tags {
+		escaped[i] = url.QueryEscape(tag)
+	}
+	edit.values().Set(string(kind)+"[].tag.tag-", strings.Join(escaped, ","))
+	return edit.LockTags(kind)
+}
+
+// LockTags stops the agent from changing the item's tags of the given kind
+func (edit *MetadataEdit) LockTags(kind MetadataTag) *MetadataEdit {
+	edit.values().Set(string(kind)+".locked", "1")
+	return edit
+}
+
+// UnlockTags lets the agent change the item's tags of the given kind again on the next refresh
+func (edit *MetadataEdit) UnlockTags(kind MetadataTag) *MetadataEdit {
+	edit.values().Set(string(kind)+".locked", "0")
+	return edit
+}
+
+func (edit *MetadataEdit) values() url.Values {
+	if edit.params == nil {
+		edit.params = url.Values{}
+		edit.tagsSet = map[MetadataTag]int{}
+	}
+	return edit.params
+}
+
+// GetMetadata returns the item with the given rating key
+func (server Server) GetMetadata(ratingKey string) (Video, error) {
+	container := &itemsResp{}
+	if err := server.fetch("GET", "/library/metadata/"+ratingKey, nil, container); err != nil {
+		return Video{}, err
+	}
+
+	if len(container.Items) == 0 {
+		return Video{}, errors.New("No item with rating key " + ratingKey)
+	}
+	item := container.Items[0]
+	item.Server = server
+	return item, nil
+}
+
+// EditMetadata applies edit to the item with the given rating key
+func (server Server) EditMetadata(ratingKey string, edit MetadataEdit) error {
+	if len(edit.params) == 0 {
+		return errors.New("Metadata edit has no changes")
+	}
+	return server.fetch("PUT", "/library/metadata/"+ratingKey, edit.params, nil)
+}
+
+// UploadPoster sets the item's poster to the image in the local file filename
+func (server Server) UploadPoster(ratingKey, filename string) error {
+	return server.uploadImage(ratingKey, "posters", filename)
+}
+
+// SetPosterURL sets the item's poster to the image the server downloads from address
+func (server Server) SetPosterURL(ratingKey, address string) error {
+	return server.setImageURL(ratingKey, "posters", address)
+}
+
+// UploadArt sets the item's background art to the image in the local file filename
+func (server Server) UploadArt(ratingKey, filename string) error {
+	return server.uploadImage(ratingKey, "arts", filename)
+}
+
+// SetArtURL sets the item's background art to the image the server downloads from address
+func (server Server) SetArtURL(ratingKey, address string) error {
+	return server.setImageURL(ratingKey, "arts", address)
+}
+
+func (server Server) uploadImage(ratingKey, kind, filename string) error {
+	image, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	req, err := server.newRequest("POST", "/library/metadata/"+ratingKey+"/"+kind, nil, bytes.NewReader(image))
+	if err != nil {
+		return err
+	}
+
+	_, err = fetchContent(req, http.StatusOK, server.Retry)
+	return err
+}
+
+func (server Server) setImageURL(ratingKey, kind, address string) error {
+	params := url.Values{}
+	params.Set("url", address)
+
+	return server.fetch("POST", "/library/metadata/"+ratingKey+"/"+kind, params, nil)
+}
@@ -0,0 +1,97 @@
+package plex
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEditMetadataSuccess(t *testing.T) {
+	expectedURL := "http://server.com:4040/library/metadata/1751?" +
+		"genre.locked=1&genre%5B0%5D.tag.tag=Action&genre%5B1%5D.tag.tag=Comedy&" +
+		"label.locked=1&label%5B%5D.tag.tag-=Old%2CStale&" +
+		"summary.locked=0&" +
+		"title.locked=1&title.value=New+Title"
+	client = makeFakeClient(t, http.StatusOK, "", makeServerRequest(t, "PUT", expectedURL))
+
+	edit := MetadataEdit{}
+	edit.Set(FieldTitle, "New Title").
+		AddTags(TagGenre, "Action").
+		AddTags(TagGenre, "Comedy").
+		RemoveTags(TagLabel, "Old", "Stale").
+		Unlock(FieldSummary)
+
+	if err := makeTestServer().EditMetadata("1751", edit); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEditMetadataRemoveTagWithComma(t *testing.T) {
+	expectedURL := "http://server.com:4040/library/metadata/1751?" +
+		"genre.locked=1&genre%5B%5D.tag.tag-=Action%252C%2BAdventure%2CSci-Fi"
+	client = makeFakeClient(t, http.StatusOK, "", makeServerRequest(t, "PUT", expectedURL))
+
+	edit := MetadataEdit{}
+	edit.RemoveTags(TagGenre, "Action, Adventure", "Sci-Fi")
+
+	if err := makeTestServer().EditMetadata("1751", edit); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEditMetadataNoChanges(t *testing.T) {
+	client = makeFakeClient(t, http.StatusOK, "", nil)
+
+	if err := makeTestServer().EditMetadata("1751", MetadataEdit{}); err == nil {
+		t.Fatal("Should err when the edit changes nothing")
+	}
+}
+
+func TestEditMetadataFail(t *testing.T) {
+	client = makeFakeClient(t, http.StatusNotFound, "", makeServerRequest(t, "PUT", "http://server.com:4040/library/metadata/1751?title.locked=1"))
+
+	edit := MetadataEdit{}
+	edit.Lock(FieldTitle)
+	if err := makeTestServer().EditMetadata("1751", edit); err == nil {
+		t.Fatal("EditMetadata returned success when it received bad status code")
+	}
+}
+
+func TestUploadPoster(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goplex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "poster.jpg")
+	if err := ioutil.WriteFile(filename, []byte("jpeg data"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedReq := makeServerRequest(t, "POST", "http://server.com:4040/library/metadata/1751/posters")
+	client = makeFakeBodyClient(t, http.StatusOK, "", expectedReq, "jpeg data")
+
+	if err := makeTestServer().UploadPoster("1751", filename); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUploadArtMissingFile(t *testing.T) {
+	client = makeFakeClient(t, http.StatusOK, "", nil)
+
+	if err := makeTestServer().UploadArt("1751", "/does/not/exist.jpg"); err == nil {
+		t.Fatal("Should err when the image file doesn't exist")
+	}
+}
+
+func TestSetArtURL(t *testing.T) {
+	expectedURL := "http://server.com:4040/library/metadata/1751/arts?url=https%3A%2F%2Fimages.com%2Fart.jpg"
+	client = makeFakeClient(t, http.StatusOK, "", makeServerRequest(t, "POST", expectedURL))
+
+	if err := makeTestServer().SetArtURL("1751", "https://images.com/art.jpg"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -2,7 +2,10 @@ package plex
 
 import (
 	"io"
+	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"reflect"
 	"strings"
 	"testing"
@@ -52,3 +55,87 @@ func makeFakeClient(t *testing.T, statusCode int, resp string, expected *http.Re
 
 	return client
 }
+
+func makeTestServer() Server {
+	return Server{
+		Device: Device{
+			ClientIdentifier: "machineID",
+			PublicAddress:    HTTPURL{url.URL{Scheme: "http", Host: "server.com:4040"}},
+			Owner:            User{AuthToken: "authToken"},
+		},
+	}
+}
+
+// makeServerRequest is a request as sent to the test server, or to plex.tv by a user with the same token
+func makeServerRequest(t *testing.T, method, rawurl string) *http.Request {
+	req, err := http.NewRequest(method, rawurl, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Add("X-Plex-Client-Identifier", "plextrack")
+	req.Header.Add("X-Plex-Token", "authToken")
+	return req
+}
+
+// startTestServer serves handler over HTTP and returns a Server that sends its requests there
+func startTestServer(t *testing.T, handler http.HandlerFunc) (*httptest.Server, Server) {
+	testServer := httptest.NewServer(handler)
+	client = testServer.Client()
+
+	address, err := url.Parse(testServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := makeTestServer()
+	server.PublicAddress = HTTPURL{*address}
+	return testServer, server
+}
+
+// makeJSONTestServer is makeTestServer asking for JSON responses
+func makeJSONTestServer() Server {
+	server := makeTestServer()
+	server.Format = FormatJSON
+	return server
+}
+
+func makeJSONServerRequest(t *testing.T, method, rawurl string) *http.Request {
+	req := makeServerRequest(t, method, rawurl)
+	req.Header.Add("Accept", "application/json")
+	return req
+}
+
+// fakeBodyRoundTripper is fakeRoundTripper for requests with a body, which can't be compared with reflect.DeepEqual
+type fakeBodyRoundTripper struct {
+	t            *testing.T
+	resp         *http.Response
+	expected     *http.Request
+	expectedBody string
+}
+
+func (f fakeBodyRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+
+	if req.Method != f.expected.Method || req.URL.String() != f.expected.URL.String() ||
+		!reflect.DeepEqual(req.Header, f.expected.Header) {
+		f.t.Errorf("Unexpected Request:\nExpected: %+v\nGot: %+v\n", f.expected, req)
+	}
+	if string(body) != f.expectedBody {
+		f.t.Errorf("Unexpected Body:\nExpected: %s\nGot: %s\n", f.expectedBody, body)
+	}
+	return f.resp, nil
+}
+
+func makeFakeBodyClient(t *testing.T, statusCode int, resp string, expected *http.Request, expectedBody string) *http.Client {
+	return &http.Client{
+		Transport: fakeBodyRoundTripper{
+			t:            t,
+			resp:         &http.Response{StatusCode: statusCode, Body: newReadCloser(resp)},
+			expected:     expected,
+			expectedBody: expectedBody,
+		},
+	}
+}
@@ -0,0 +1,76 @@
+package plex
+
+import (
+	"net/url"
+	"strconv"
+)
+
+type PlayQueue struct {
+	ID                     int       `xml:"playQueueID,attr" json:"playQueueID"`
+	SelectedItemID         int       `xml:"playQueueSelectedItemID,attr" json:"playQueueSelectedItemID"`
+	SelectedItemOffset     int       `xml:"playQueueSelectedItemOffset,attr" json:"playQueueSelectedItemOffset"`
+	SelectedMetadataItemID string    `xml:"playQueueSelectedMetadataItemID,attr" json:"playQueueSelectedMetadataItemID"`
+	Shuffled               IntAsBool `xml:"playQueueShuffled,attr" json:"playQueueShuffled"`
+	SourceURI              string    `xml:"playQueueSourceURI,attr" json:"playQueueSourceURI"`
+	TotalCount             int       `xml:"playQueueTotalCount,attr" json:"playQueueTotalCount"`
+	Version                int       `xml:"playQueueVersion,attr" json:"playQueueVersion"`
+	Items                  []Video   `xml:",any" json:"Metadata"`
+	Server                 Server    `xml:"-" json:"-"`
+}
+
+type PlayQueueOptions struct {
+	// The kind of media in the queue: video, audio or photo. Defaults to video.
+	Type       string
+	Shuffle    bool
+	Repeat     bool
+	Continuous bool
+}
+
+// CreatePlayQueue creates a play queue starting with the item with the given rating key. When the item is a
+// show, season or album the queue contains all of its children.
+func (server Server) CreatePlayQueue(ratingKey string, opts PlayQueueOptions) (PlayQueue, error) {
+	params := opts.params()
+	params.Set("uri", server.libraryURI("/library/metadata/"+ratingKey))
+
+	return server.createPlayQueue(params)
+}
+
+func (server Server) CreatePlaylistPlayQueue(playlistID string, opts PlayQueueOptions) (PlayQueue, error) {
+	params := opts.params()
+	params.Set("playlistID", playlistID)
+
+	return server.createPlayQueue(params)
+}
+
+func (server Server) createPlayQueue(params url.Values) (PlayQueue, error) {
+	queue := PlayQueue{}
+	if err := server.fetch("POST", "/playQueues", params, &queue); err != nil {
+		return PlayQueue{}, err
+	}
+
+	queue.Server = server
+	for i := range queue.Items {
+		queue.Items[i].Server = server
+	}
+	return queue, nil
+}
+
+func (opts PlayQueueOptions) params() url.Values {
+	mediaType := opts.Type
+	if mediaType == "" {
+		mediaType = "video"
+	}
+
+	params := url.Values{}
+	params.Set("type", mediaType)
+	params.Set("shuffle", boolParam(opts.Shuffle))
+	params.Set("repeat", boolParam(opts.Repeat))
+	params.Set("continuous", boolParam(opts.Continuous))
+	params.Set("own", "1")
+	return params
+}
+
+// containerKey is the key players use to fetch the contents of the queue
+func (queue PlayQueue) containerKey() string {
+	return "/playQueues/" + strconv.Itoa(queue.ID) + "?own=1&window=200"
+}
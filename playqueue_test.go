@@ -0,0 +1,107 @@
+package plex
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestCreatePlayQueueSuccess(t *testing.T) {
+	resp := `<?xml version="1.0" encoding="UTF-8"?>
+	<MediaContainer size="2" playQueueID="42" playQueueSelectedItemID="100" playQueueSelectedItemOffset="0" playQueueSelectedMetadataItemID="1751" playQueueShuffled="1" playQueueSourceURI="library://x/item/%2Flibrary%2Fmetadata%2F1751" playQueueTotalCount="2" playQueueVersion="1">
+	  <Video playQueueItemID="100" ratingKey="1751" key="/library/metadata/1751" type="episode" title="Episode 21" />
+	  <Video playQueueItemID="101" ratingKey="1752" key="/library/metadata/1752" type="episode" title="Episode 22" />
+	</MediaContainer>`
+
+	expectedReq := makeServerRequest(t, "POST", "http://server.com:4040/playQueues?"+
+		"continuous=0&own=1&repeat=0&shuffle=1&type=video&"+
+		"uri=server%3A%2F%2FmachineID%2Fcom.plexapp.plugins.library%2Flibrary%2Fmetadata%2F1751")
+	client = makeFakeClient(t, http.StatusOK, resp, expectedReq)
+
+	result, err := makeTestServer().CreatePlayQueue("1751", PlayQueueOptions{Shuffle: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := PlayQueue{
+		ID:                     42,
+		SelectedItemID:         100,
+		SelectedMetadataItemID: "1751",
+		Shuffled:               true,
+		SourceURI:              "library://x/item/%2Flibrary%2Fmetadata%2F1751",
+		TotalCount:             2,
+		Version:                1,
+		Items: []Video{
+			Video{RatingKey: "1751", Key: "/library/metadata/1751", Type: "episode", Title: "Episode 21", Server: makeTestServer()},
+			Video{RatingKey: "1752", Key: "/library/metadata/1752", Type: "episode", Title: "Episode 22", Server: makeTestServer()},
+		},
+		Server: makeTestServer(),
+	}
+
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("\nExpected: %+v\n\nGot: %+v", expected, result)
+	}
+}
+
+func TestCreatePlayQueueJSONSuccess(t *testing.T) {
+	resp := `{"MediaContainer": {"size": 1, "playQueueID": 42, "playQueueSelectedItemID": 100, "playQueueSelectedItemOffset": 0, "playQueueSelectedMetadataItemID": "1751", "playQueueShuffled": true, "playQueueSourceURI": "library://x/item/%2Flibrary%2Fmetadata%2F1751", "playQueueTotalCount": 1, "playQueueVersion": 1, "Metadata": [
+	  {"playQueueItemID": 100, "ratingKey": "1751", "key": "/library/metadata/1751", "type": "episode", "title": "Episode 21"}
+	]}}`
+
+	expectedReq := makeJSONServerRequest(t, "POST", "http://server.com:4040/playQueues?"+
+		"continuous=0&own=1&repeat=0&shuffle=0&type=video&"+
+		"uri=server%3A%2F%2FmachineID%2Fcom.plexapp.plugins.library%2Flibrary%2Fmetadata%2F1751")
+	client = makeFakeClient(t, http.StatusOK, resp, expectedReq)
+
+	result, err := makeJSONTestServer().CreatePlayQueue("1751", PlayQueueOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := PlayQueue{
+		ID:                     42,
+		SelectedItemID:         100,
+		SelectedMetadataItemID: "1751",
+		Shuffled:               true,
+		SourceURI:              "library://x/item/%2Flibrary%2Fmetadata%2F1751",
+		TotalCount:             1,
+		Version:                1,
+		Items: []Video{
+			Video{RatingKey: "1751", Key: "/library/metadata/1751", Type: "episode", Title: "Episode 21", Server: makeJSONTestServer()},
+		},
+		Server: makeJSONTestServer(),
+	}
+
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("\nExpected: %+v\n\nGot: %+v", expected, result)
+	}
+}
+
+func TestCreatePlaylistPlayQueueSuccess(t *testing.T) {
+	resp := `<?xml version="1.0" encoding="UTF-8"?>
+	<MediaContainer size="0" playQueueID="43" playQueueSelectedMetadataItemID="20" playQueueTotalCount="0" playQueueVersion="1" />`
+
+	expectedReq := makeServerRequest(t, "POST", "http://server.com:4040/playQueues?"+
+		"continuous=0&own=1&playlistID=7&repeat=1&shuffle=0&type=audio")
+	client = makeFakeClient(t, http.StatusOK, resp, expectedReq)
+
+	result, err := makeTestServer().CreatePlaylistPlayQueue("7", PlayQueueOptions{Type: "audio", Repeat: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.ID != 43 || result.SelectedMetadataItemID != "20" {
+		t.Fatalf("Unexpected play queue %+v", result)
+	}
+}
+
+func TestCreatePlayQueueFail(t *testing.T) {
+	expectedReq := makeServerRequest(t, "POST", "http://server.com:4040/playQueues?"+
+		"continuous=0&own=1&repeat=0&shuffle=0&type=video&"+
+		"uri=server%3A%2F%2FmachineID%2Fcom.plexapp.plugins.library%2Flibrary%2Fmetadata%2F1751")
+	client = makeFakeClient(t, http.StatusBadRequest, "", expectedReq)
+
+	if _, err := makeTestServer().CreatePlayQueue("1751", PlayQueueOptions{}); err == nil {
+		t.Fatal("CreatePlayQueue returned success when it received bad status code")
+	}
+}
@@ -1,27 +1,42 @@
 package plex
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"encoding/xml"
 	"errors"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strconv"
+	"time"
 )
 
 const plexTVURL = "https://plex.tv"
 const clientIdentifier = "plextrack"
 
 type devicesResp struct {
-	XMLName       xml.Name `xml:"MediaContainer"`
-	PublicAddress HTTPURL  `xml:"publicAddress,attr"`
-	Devices       []Device `xml:"Device"`
+	XMLName       xml.Name `xml:"MediaContainer" json:"-"`
+	PublicAddress HTTPURL  `xml:"publicAddress,attr" json:"publicAddress"`
+	Devices       []Device `xml:"Device" json:"Device"`
 }
 
 type sessionsResp struct {
-	XMLName xml.Name `xml:"MediaContainer"`
-	Videos  []Video  `xml:"Video"`
+	XMLName xml.Name `xml:"MediaContainer" json:"-"`
+	Videos  []Video  `xml:"Video" json:"Metadata"`
 }
 
+// WireFormat is the format responses are requested in from a server. JSON responses are smaller and faster to
+// decode for large libraries. Requests to plex.tv always use XML.
+type WireFormat int
+
+const (
+	FormatXML WireFormat = iota
+	FormatJSON
+)
+
 // Hook to override for tests
 var client = http.DefaultClient
 
@@ -33,7 +48,7 @@ func GetUser(username, password string) (User, error) {
 	req.SetBasicAuth(username, password)
 	req.Header.Add("X-Plex-Client-Identifier", clientIdentifier)
 
-	resp, err := fetchContent(req, http.StatusCreated)
+	resp, err := fetchContent(req, http.StatusCreated, RetryPolicy{})
 	if err != nil {
 		return User{}, err
 	}
@@ -48,19 +63,8 @@ func GetUser(username, password string) (User, error) {
 }
 
 func (user User) GetDevices() ([]Device, error) {
-	req, err := http.NewRequest("GET", plexTVURL+"/devices.xml", nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Add("X-Plex-Client-Identifier", clientIdentifier)
-	req.Header.Add("X-Plex-Token", user.AuthToken)
-
-	content, err := fetchContent(req, http.StatusOK)
-
 	resp := &devicesResp{}
-
-	err = xml.Unmarshal(content, resp)
-	if err != nil {
+	if err := user.fetch("GET", "/devices.xml", nil, resp); err != nil {
 		return nil, err
 	}
 
@@ -79,49 +83,195 @@ func (user User) GetServers() ([]Server, error) {
 
 	var servers []Server
 	for _, device := range devices {
+		if device.Kind() != DeviceKindServer {
+			continue
+		}
+
 		server, err := device.toServer()
-		if err == nil {
-			servers = append(servers, server)
+		if err != nil {
+			return nil, err
 		}
+		servers = append(servers, server)
 	}
 
 	return servers, nil
 }
 
-func (server Server) GetActivity() ([]Video, error) {
-	server.PublicAddress.Path = "/status/sessions"
+// GetPlayers returns the user's devices that can play media, the devices whose Kind is DeviceKindPlayer
+func (user User) GetPlayers() ([]Device, error) {
+	devices, err := user.GetDevices()
+	if err != nil {
+		return nil, err
+	}
 
-	req, err := http.NewRequest("GET", server.PublicAddress.String(), nil)
+	var players []Device
+	for _, device := range devices {
+		if device.Kind() == DeviceKindPlayer {
+			players = append(players, device)
+		}
+	}
+
+	return players, nil
+}
+
+// GetDevicesByFeature returns the user's devices that provide feature, e.g. server, player or sync-target
+func (user User) GetDevicesByFeature(feature string) ([]Device, error) {
+	devices, err := user.GetDevices()
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Add("X-Plex-Client-Identifier", clientIdentifier)
-	req.Header.Add("X-Plex-Token", server.Owner.AuthToken)
 
-	resp, err := fetchContent(req, http.StatusOK)
+	var matching []Device
+	for _, device := range devices {
+		if device.ProvidesFeature(feature) {
+			matching = append(matching, device)
+		}
+	}
+
+	return matching, nil
+}
+
+func (user User) newRequest(method, path string, params url.Values, body io.Reader) (*http.Request, error) {
+	address := plexTVURL + path
+	if len(params) > 0 {
+		address += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequest(method, address, body)
 	if err != nil {
 		return nil, err
 	}
+	req.Header.Add("X-Plex-Client-Identifier", clientIdentifier)
+	req.Header.Add("X-Plex-Token", user.AuthToken)
+
+	return req, nil
+}
+
+// fetch makes a request to plex.tv as the user and decodes the XML response into v. If v is nil the
+// response body is discarded.
+func (user User) fetch(method, path string, params url.Values, v interface{}) error {
+	req, err := user.newRequest(method, path, params, nil)
+	if err != nil {
+		return err
+	}
 
+	return fetchXML(req, user.Retry, v)
+}
+
+// fetchJSON is fetch with body sent as JSON
+func (user User) fetchJSON(method, path string, body interface{}, v interface{}) error {
+	content, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := user.newRequest(method, path, nil, bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+
+	return fetchXML(req, user.Retry, v)
+}
+
+func fetchXML(req *http.Request, policy RetryPolicy, v interface{}) error {
+	content, err := fetchContent(req, http.StatusOK, policy)
+	if err != nil {
+		return err
+	}
+
+	if v == nil {
+		return nil
+	}
+	return xml.Unmarshal(content, v)
+}
+
+func (server Server) GetActivity() ([]Video, error) {
+	return server.GetActivityContext(context.Background())
+}
+
+// GetActivityContext is GetActivity with a context to cancel the request or give it a deadline
+func (server Server) GetActivityContext(ctx context.Context) ([]Video, error) {
 	container := &sessionsResp{}
-	if err := xml.Unmarshal(resp, container); err != nil {
+	if err := server.fetchContext(ctx, "GET", "/status/sessions", nil, container); err != nil {
 		return nil, err
 	}
 
+	for i := range container.Videos {
+		container.Videos[i].Server = server
+	}
+
 	return container.Videos, nil
 }
 
-func fetchContent(req *http.Request, expectedStatusCode int) ([]byte, error) {
-	resp, err := client.Do(req)
+func (server Server) newRequest(method, path string, params url.Values, body io.Reader) (*http.Request, error) {
+	address := server.PublicAddress
+	address.Path = path
+	address.RawQuery = params.Encode()
+
+	req, err := http.NewRequest(method, address.String(), body)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
+	req.Header.Add("X-Plex-Client-Identifier", clientIdentifier)
+	req.Header.Add("X-Plex-Token", server.Owner.AuthToken)
+	if server.Format == FormatJSON {
+		req.Header.Add("Accept", "application/json")
+	}
+
+	return req, nil
+}
+
+// fetch makes a request against the server and decodes the response into v. If v is nil the
+// response body is discarded.
+func (server Server) fetch(method, path string, params url.Values, v interface{}) error {
+	return server.fetchContext(context.Background(), method, path, params, v)
+}
+
+func (server Server) fetchContext(ctx context.Context, method, path string, params url.Values, v interface{}) error {
+	req, err := server.newRequest(method, path, params, nil)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+
+	resp, err := fetchContent(req, http.StatusOK, server.Retry)
+	if err != nil {
+		return err
+	}
+
+	if v == nil {
+		return nil
+	}
+	return decode(req, resp, v)
+}
 
-	if resp.StatusCode != expectedStatusCode {
-		return nil, errors.New("Received status: " + strconv.Itoa(resp.StatusCode) +
-			" expected status: " + strconv.Itoa(expectedStatusCode))
+// decode unmarshals a server response in whichever format req asked for
+func decode(req *http.Request, content []byte, v interface{}) error {
+	if req.Header.Get("Accept") != "application/json" {
+		return xml.Unmarshal(content, v)
+	}
+
+	// JSON responses wrap the attributes and children of the MediaContainer in an object of the same name
+	container := struct {
+		MediaContainer json.RawMessage
+	}{}
+	if err := json.Unmarshal(content, &container); err != nil {
+		return err
+	}
+	if container.MediaContainer == nil {
+		return errors.New("Response is missing a MediaContainer")
+	}
+
+	return json.Unmarshal(container.MediaContainer, v)
+}
+
+func fetchContent(req *http.Request, expectedStatusCode int, policy RetryPolicy) ([]byte, error) {
+	resp, err := doRequest(req, expectedStatusCode, policy)
+	if err != nil {
+		return nil, err
 	}
+	defer resp.Body.Close()
 
 	contents, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
@@ -130,3 +280,36 @@ func fetchContent(req *http.Request, expectedStatusCode int) ([]byte, error) {
 
 	return contents, nil
 }
+
+// doRequest makes the request and checks the status code, retrying transient failures according to policy.
+// The caller must close the body of the returned response.
+func doRequest(req *http.Request, expectedStatusCode int, policy RetryPolicy) (*http.Response, error) {
+	policy = policy.orDefault()
+	started := time.Now()
+
+	for attempt := 1; ; attempt++ {
+		resp, err := client.Do(req)
+		if err == nil && resp.StatusCode == expectedStatusCode {
+			return resp, nil
+		}
+		if err == nil {
+			resp.Body.Close()
+			err = errors.New("Received status: " + strconv.Itoa(resp.StatusCode) +
+				" expected status: " + strconv.Itoa(expectedStatusCode))
+		}
+
+		delay, retry := policy.delay(req, resp, attempt, time.Since(started))
+		if !retry {
+			return nil, err
+		}
+		if waitErr := wait(req.Context(), delay); waitErr != nil {
+			return nil, err
+		}
+
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
@@ -1,9 +1,11 @@
 package plex
 
 import (
+	"errors"
 	"net/http"
 	"net/url"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -114,18 +116,20 @@ func TestGetDevicesSuccess(t *testing.T) {
 
 	expected := []Device{
 		Device{
-			Name:          "My Nexus 5",
-			PublicAddress: HTTPURL{url.URL{Scheme: "http", Host: "24.56.78.91"}},
-			Product:       "Plex for Android",
-			Provides:      []string{"controller", "sync-target"},
-			Connections:   []Connection{Connection{HTTPURL{url.URL{Scheme: "http", Host: "192.168.1.1:32400"}}}},
-			Owner:         user,
+			Name:             "My Nexus 5",
+			ClientIdentifier: "caac4066dbaa6a9c-com-plexapp-android",
+			PublicAddress:    HTTPURL{url.URL{Scheme: "http", Host: "24.56.78.91"}},
+			Product:          "Plex for Android",
+			Provides:         []string{"controller", "sync-target"},
+			Connections:      []Connection{Connection{HTTPURL{url.URL{Scheme: "http", Host: "192.168.1.1:32400"}}}},
+			Owner:            user,
 		},
 		Device{
-			Name:          "Server",
-			PublicAddress: HTTPURL{url.URL{Scheme: "http", Host: "serverPublicAddress.com"}},
-			Product:       "Plex Media Server",
-			Provides:      []string{"server"},
+			Name:             "Server",
+			ClientIdentifier: "clientIdentifier",
+			PublicAddress:    HTTPURL{url.URL{Scheme: "http", Host: "serverPublicAddress.com"}},
+			Product:          "Plex Media Server",
+			Provides:         []string{"server"},
 			Connections: []Connection{
 				Connection{HTTPURL{url.URL{Scheme: "http", Host: "serverPublicAddress.com:12345"}}},
 				Connection{HTTPURL{url.URL{Scheme: "http", Host: "192.168.1.2:32400"}}},
@@ -173,6 +177,7 @@ func TestGetServersSuccess(t *testing.T) {
 
 	user := User{
 		AuthToken: "authToken",
+		Retry:     RetryPolicy{MaxAttempts: 1},
 	}
 
 	expectedReq, err := http.NewRequest("GET", "https://plex.tv/devices.xml", nil)
@@ -187,11 +192,12 @@ func TestGetServersSuccess(t *testing.T) {
 
 	expected := []Server{
 		Server{
-			Device{
-				Name:          "Server",
-				PublicAddress: HTTPURL{url.URL{Scheme: "http", Host: "serverPublicAddress.com:12345"}},
-				Product:       "Plex Media Server",
-				Provides:      []string{"server"},
+			Device: Device{
+				Name:             "Server",
+				ClientIdentifier: "clientIdentifier",
+				PublicAddress:    HTTPURL{url.URL{Scheme: "http", Host: "serverPublicAddress.com:12345"}},
+				Product:          "Plex Media Server",
+				Provides:         []string{"server"},
 				Connections: []Connection{
 					Connection{HTTPURL{url.URL{Scheme: "http", Host: "serverPublicAddress.com:12345"}}},
 					Connection{HTTPURL{url.URL{Scheme: "http", Host: "192.168.1.2:32400"}}},
@@ -199,6 +205,7 @@ func TestGetServersSuccess(t *testing.T) {
 				},
 				Owner: user,
 			},
+			Retry: user.Retry,
 		},
 	}
 
@@ -247,7 +254,7 @@ func TestGetActivitySuccess(t *testing.T) {
 	client = makeFakeClient(t, http.StatusOK, resp, expectedReq)
 
 	server := Server{
-		Device{
+		Device: Device{
 			PublicAddress: HTTPURL{url.URL{Scheme: "http", Host: "server.com:4040"}},
 			Owner:         User{AuthToken: "authToken"},
 		},
@@ -258,7 +265,63 @@ func TestGetActivitySuccess(t *testing.T) {
 		t.Fatalf("Unexpected error: %s", err)
 	}
 
-	expected := []Video{
+	expected := makeExpectedActivity()
+	expected[0].Server = server
+
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("\nExpected: %+v\n\nGot: %+v", expected, result)
+	}
+}
+
+func TestGetActivityJSONSuccess(t *testing.T) {
+	resp := `{"MediaContainer": {"size": 1, "Metadata": [{
+		"addedAt": 1430373171, "art": "/library/metadata/181/art/1430373196", "chapterSource": "chapterSource",
+		"contentRating": "TV-PG", "duration": 1297172, "grandparentArt": "/library/metadata/181/art/1430373196",
+		"grandparentKey": "/library/metadata/181", "grandparentRatingKey": "181",
+		"grandparentTheme": "/library/metadata/181/theme/1430373196",
+		"grandparentThumb": "/library/metadata/181/thumb/1430373196", "grandparentTitle": "Modern Family",
+		"guid": "com.plexapp.agents.thetvdb://95011/6/21?lang=en", "index": 21, "key": "/library/metadata/1751",
+		"librarySectionID": "1", "parentIndex": 6, "parentKey": "/library/metadata/1117", "parentRatingKey": "1117",
+		"parentThumb": "/library/metadata/1117/thumb/1430373196", "ratingKey": "1751", "sessionKey": "11",
+		"summary": "", "thumb": "/library/metadata/1751/thumb/1430373196", "title": "Episode 21", "type": "episode",
+		"updatedAt": 1430373196,
+		"Media": [{"aspectRatio": 1.78, "audioChannels": 6, "audioCodec": "ac3", "bitrate": 3874, "container": "mkv",
+			"duration": 1297172, "height": 720, "id": 1950, "videoCodec": "h264", "videoFrameRate": "24p",
+			"videoResolution": "720", "width": 1280,
+			"Part": [{"container": "mkv", "duration": 1297172, "id": 2147, "key": "/library/parts/2147/file.mkv",
+				"file": "/media/Media/TV/Modern Family/Season 6/Modern Family - S06E21 - Integrity.mkv",
+				"size": 628172169,
+				"Stream": [{"bitrate": 3413, "codec": "h264", "height": 720, "id": 10812, "index": 0,
+					"language": "English", "languageCode": "eng", "streamType": 1, "width": 1280},
+					{"bitrate": 384, "channels": 6, "codec": "ac3", "id": 10813, "index": 1, "selected": true,
+					"streamType": 2}]}]}],
+		"User": {"id": "1", "thumb": "http://www.thumb.com", "title": "title"},
+		"Player": {"machineIdentifier": "5418fbf4404066f0-com-plexapp-android", "platform": "Android",
+			"product": "Plex for Android", "state": "playing", "title": "My Nexus 7"},
+		"TranscodeSession": {"key": "5418fbf4404066f0-com-plexapp-android", "throttled": true,
+			"progress": 2.0999999046325684, "speed": 2.0999999046325684, "duration": 1297000,
+			"videoDecision": "transcode", "audioDecision": "transcode", "protocol": "hls", "container": "mpegts",
+			"videoCodec": "h264", "audioCodec": "aac", "audioChannels": 2, "width": 1280, "height": 720}
+	}]}}`
+
+	expectedReq := makeJSONServerRequest(t, "GET", "http://server.com:4040/status/sessions")
+	client = makeFakeClient(t, http.StatusOK, resp, expectedReq)
+
+	server := makeJSONTestServer()
+	result, err := server.GetActivity()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := makeExpectedActivity()
+	expected[0].Server = server
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("\nExpected: %+v\n\nGot: %+v", expected, result)
+	}
+}
+
+func makeExpectedActivity() []Video {
+	return []Video{
 		Video{
 			AddedAt:          UnixTime{time.Unix(1430373171, 0)},
 			Art:              URLPath{url.URL{Path: "/library/metadata/181/art/1430373196"}},
@@ -269,18 +332,57 @@ func TestGetActivitySuccess(t *testing.T) {
 			GrandparentThumb: URLPath{url.URL{Path: "/library/metadata/181/thumb/1430373196"}},
 			GrandparentTitle: "Modern Family",
 			GUID:             "com.plexapp.agents.thetvdb://95011/6/21?lang=en",
+			Key:              "/library/metadata/1751",
+			LibrarySectionID: "1",
 			ParentThumb:      URLPath{url.URL{Path: "/library/metadata/1117/thumb/1430373196"}},
+			RatingKey:        "1751",
 			Thumb:            URLPath{url.URL{Path: "/library/metadata/1751/thumb/1430373196"}},
 			Title:            "Episode 21",
+			Type:             "episode",
 			UpdatedAt:        UnixTime{time.Unix(1430373196, 0)},
 			Media: Media{
-				AspectRatio:    1.78,
-				AudioChannels:  6,
-				AudioCodec:     "ac3",
-				VideoCodec:     "h264",
-				VideoFrameRate: "24p",
-				HeightPx:       720,
-				WidthPx:        1280,
+				AspectRatio:     1.78,
+				AudioChannels:   6,
+				AudioCodec:      "ac3",
+				Bitrate:         3874,
+				Container:       "mkv",
+				VideoCodec:      "h264",
+				VideoFrameRate:  "24p",
+				HeightPx:        720,
+				WidthPx:         1280,
+				VideoResolution: "720",
+				Parts: []Part{
+					Part{
+						ID:        2147,
+						Key:       URLPath{url.URL{Path: "/library/parts/2147/file.mkv"}},
+						File:      "/media/Media/TV/Modern Family/Season 6/Modern Family - S06E21 - Integrity.mkv",
+						Size:      628172169,
+						Container: "mkv",
+						Duration:  MillisDuration(time.Duration(1297172) * time.Millisecond),
+						Streams: []Stream{
+							Stream{
+								ID:           10812,
+								StreamType:   StreamTypeVideo,
+								Index:        0,
+								Codec:        "h264",
+								Bitrate:      3413,
+								Language:     "English",
+								LanguageCode: "eng",
+								Width:        1280,
+								Height:       720,
+							},
+							Stream{
+								ID:         10813,
+								StreamType: StreamTypeAudio,
+								Index:      1,
+								Codec:      "ac3",
+								Bitrate:    384,
+								Channels:   6,
+								Selected:   true,
+							},
+						},
+					},
+				},
 			},
 			User: User{
 				ID:    1,
@@ -312,10 +414,6 @@ func TestGetActivitySuccess(t *testing.T) {
 			},
 		},
 	}
-
-	if !reflect.DeepEqual(expected, result) {
-		t.Fatalf("\nExpected: %+v\n\nGot: %+v", expected, result)
-	}
 }
 
 func TestGetActivityFail(t *testing.T) {
@@ -325,7 +423,7 @@ func TestGetActivityFail(t *testing.T) {
 	client = makeFakeClient(t, http.StatusUnauthorized, "", expectedReq)
 
 	server := Server{
-		Device{
+		Device: Device{
 			PublicAddress: HTTPURL{url.URL{Scheme: "http", Host: "server.com:4040"}},
 			Owner:         User{AuthToken: "authToken"},
 		},
@@ -335,3 +433,76 @@ func TestGetActivityFail(t *testing.T) {
 		t.Fatal("GetActivity returned success when it received bad status code")
 	}
 }
+
+func TestGetDevicesRequestError(t *testing.T) {
+	_, restore := recordWaits()
+	defer restore()
+
+	expectedReq, err := http.NewRequest("GET", "https://plex.tv/devices.xml", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedReq.Header.Add("X-Plex-Client-Identifier", "plextrack")
+	expectedReq.Header.Add("X-Plex-Token", "authToken")
+
+	client = &http.Client{
+		Transport: fakeRoundTripper{
+			t:        t,
+			err:      errors.New("connection reset"),
+			expected: expectedReq,
+		},
+	}
+
+	user := User{AuthToken: "authToken"}
+	if _, err = user.GetDevices(); err == nil || !strings.Contains(err.Error(), "connection reset") {
+		t.Fatalf("Expected the request error to be returned, got %v", err)
+	}
+}
+
+const devicesByFeatureResp = `<?xml version="1.0" encoding="UTF-8"?>
+	<MediaContainer publicAddress="serverPublicAddress.com">
+	  <Device name="Phone" clientIdentifier="phone" provides="client,controller,sync-target,player,pubsub-player" />
+	  <Device name="Remote" clientIdentifier="remote" provides="controller" />
+	  <Device name="Server" clientIdentifier="server" provides="server" />
+	  <Device name="Browser" clientIdentifier="browser" provides="client" />
+	</MediaContainer>`
+
+func TestGetPlayersSuccess(t *testing.T) {
+	expectedReq, err := http.NewRequest("GET", "https://plex.tv/devices.xml", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedReq.Header.Add("X-Plex-Client-Identifier", "plextrack")
+	expectedReq.Header.Add("X-Plex-Token", "authToken")
+	client = makeFakeClient(t, http.StatusOK, devicesByFeatureResp, expectedReq)
+
+	user := User{AuthToken: "authToken"}
+	result, err := user.GetPlayers()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result) != 2 || result[0].Name != "Phone" || result[1].Name != "Browser" {
+		t.Fatalf("Expected the phone and the browser, got %+v", result)
+	}
+}
+
+func TestGetDevicesByFeatureSuccess(t *testing.T) {
+	expectedReq, err := http.NewRequest("GET", "https://plex.tv/devices.xml", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedReq.Header.Add("X-Plex-Client-Identifier", "plextrack")
+	expectedReq.Header.Add("X-Plex-Token", "authToken")
+	client = makeFakeClient(t, http.StatusOK, devicesByFeatureResp, expectedReq)
+
+	user := User{AuthToken: "authToken"}
+	result, err := user.GetDevicesByFeature("controller")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result) != 2 || result[0].Name != "Phone" || result[1].Name != "Remote" {
+		t.Fatalf("Expected the phone and the remote, got %+v", result)
+	}
+}
@@ -0,0 +1,171 @@
+package plex
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type settingsResp struct {
+	XMLName  xml.Name  `xml:"MediaContainer" json:"-"`
+	Settings []Setting `xml:"Setting" json:"Setting"`
+}
+
+// Setting is a server preference. Type is one of bool, int, double or text.
+type Setting struct {
+	ID         string       `xml:"id,attr" json:"id"`
+	Label      string       `xml:"label,attr" json:"label"`
+	Summary    string       `xml:"summary,attr" json:"summary"`
+	Type       string       `xml:"type,attr" json:"type"`
+	Default    SettingValue `xml:"default,attr" json:"default"`
+	Value      SettingValue `xml:"value,attr" json:"value"`
+	Group      string       `xml:"group,attr" json:"group"`
+	Hidden     IntAsBool    `xml:"hidden,attr" json:"hidden"`
+	Advanced   IntAsBool    `xml:"advanced,attr" json:"advanced"`
+	EnumValues string       `xml:"enumValues,attr" json:"enumValues"`
+	Server     Server       `xml:"-" json:"-"`
+}
+
+// SettingValue is the value of a setting as text. JSON responses send typed values, which are converted to the
+// same text XML uses.
+type SettingValue string
+
+func (v *SettingValue) UnmarshalJSON(data []byte) error {
+	value, err := jsonValue(data)
+	*v = SettingValue(value)
+	return err
+}
+
+func (v SettingValue) Bool() (bool, error) {
+	return strconv.ParseBool(string(v))
+}
+
+func (v SettingValue) Int() (int64, error) {
+	return strconv.ParseInt(string(v), 10, 64)
+}
+
+func (v SettingValue) Float() (float64, error) {
+	return strconv.ParseFloat(string(v), 64)
+}
+
+// EnumValue is one of the allowed values of a setting, with the label shown for it
+type EnumValue struct {
+	Value string
+	Label string
+}
+
+// Enum returns the values the setting is restricted to, or nil if any value of its type is allowed
+func (setting Setting) Enum() []EnumValue {
+	if setting.EnumValues == "" {
+		return nil
+	}
+
+	var values []EnumValue
+	for _, option := range strings.Split(setting.EnumValues, "|") {
+		parts := strings.SplitN(option, ":", 2)
+		if len(parts) == 1 {
+			values = append(values, EnumValue{Value: parts[0], Label: parts[0]})
+		} else {
+			values = append(values, EnumValue{Value: parts[0], Label: parts[1]})
+		}
+	}
+	return values
+}
+
+func (server Server) GetPreferences() ([]Setting, error) {
+	container := &settingsResp{}
+	if err := server.fetch("GET", "/:/prefs", nil, container); err != nil {
+		return nil, err
+	}
+
+	for i := range container.Settings {
+		container.Settings[i].Server = server
+	}
+
+	return container.Settings, nil
+}
+
+// SetPreference changes the setting with the given id. See Setting.Set for the values accepted.
+func (server Server) SetPreference(id string, value interface{}) error {
+	settings, err := server.GetPreferences()
+	if err != nil {
+		return err
+	}
+
+	for _, setting := range settings {
+		if setting.ID == id {
+			return setting.Set(value)
+		}
+	}
+	return fmt.Errorf("Server has no setting %s", id)
+}
+
+// Set changes the value of the setting on the server. Value may be a string in the setting's format, or a bool,
+// integer or float for settings of the matching type. It is checked against the setting's type and enum values
+// before anything is sent.
+func (setting Setting) Set(value interface{}) error {
+	text, err := setting.format(value)
+	if err != nil {
+		return err
+	}
+
+	params := url.Values{}
+	params.Set(setting.ID, text)
+
+	return setting.Server.fetch("PUT", "/:/prefs", params, nil)
+}
+
+func (setting Setting) format(value interface{}) (string, error) {
+	var text string
+	switch v := value.(type) {
+	case string:
+		text = v
+	case bool:
+		if setting.Type != "bool" {
+			return "", setting.typeError(value)
+		}
+		text = strconv.FormatBool(v)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		if setting.Type != "int" && setting.Type != "double" {
+			return "", setting.typeError(value)
+		}
+		text = fmt.Sprint(v)
+	case float32, float64:
+		if setting.Type != "double" {
+			return "", setting.typeError(value)
+		}
+		text = fmt.Sprint(v)
+	default:
+		return "", setting.typeError(value)
+	}
+
+	var err error
+	switch setting.Type {
+	case "bool":
+		_, err = SettingValue(text).Bool()
+	case "int":
+		_, err = SettingValue(text).Int()
+	case "double":
+		_, err = SettingValue(text).Float()
+	}
+	if err != nil {
+		return "", setting.typeError(value)
+	}
+
+	enum := setting.Enum()
+	if enum == nil {
+		return text, nil
+	}
+	for _, option := range enum {
+		if option.Value == text {
+			return text, nil
+		}
+	}
+	return "", fmt.Errorf("%v is not one of the allowed values for %s: %s", value, setting.ID, setting.EnumValues)
+}
+
+func (setting Setting) typeError(value interface{}) error {
+	return fmt.Errorf("%v is not a valid %s value for %s", value, setting.Type, setting.ID)
+}
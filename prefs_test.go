@@ -0,0 +1,136 @@
+package plex
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+const prefsResp = `<?xml version="1.0" encoding="UTF-8"?>
+	<MediaContainer size="3">
+	  <Setting id="FriendlyName" label="Friendly name" summary="This name will be used to identify this media server." type="text" default="" value="Server" hidden="0" advanced="0" group="general" />
+	  <Setting id="logDebug" label="Enable Plex Media Server debug logging" summary="" type="bool" default="true" value="false" hidden="0" advanced="1" group="general" />
+	  <Setting id="TranscoderQuality" label="Transcoder quality" summary="" type="int" default="0" value="2" hidden="0" advanced="0" group="transcoder" enumValues="0:Automatic|1:Prefer higher speed encoding|2:Prefer higher quality encoding|3:Make my CPU hurt" />
+	</MediaContainer>`
+
+func TestGetPreferencesSuccess(t *testing.T) {
+	client = makeFakeClient(t, http.StatusOK, prefsResp, makeServerRequest(t, "GET", "http://server.com:4040/:/prefs"))
+
+	result, err := makeTestServer().GetPreferences()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Setting{
+		Setting{
+			ID:      "FriendlyName",
+			Label:   "Friendly name",
+			Summary: "This name will be used to identify this media server.",
+			Type:    "text",
+			Value:   "Server",
+			Group:   "general",
+			Server:  makeTestServer(),
+		},
+		Setting{
+			ID:       "logDebug",
+			Label:    "Enable Plex Media Server debug logging",
+			Type:     "bool",
+			Default:  "true",
+			Value:    "false",
+			Group:    "general",
+			Advanced: true,
+			Server:   makeTestServer(),
+		},
+		Setting{
+			ID:         "TranscoderQuality",
+			Label:      "Transcoder quality",
+			Type:       "int",
+			Default:    "0",
+			Value:      "2",
+			Group:      "transcoder",
+			EnumValues: "0:Automatic|1:Prefer higher speed encoding|2:Prefer higher quality encoding|3:Make my CPU hurt",
+			Server:     makeTestServer(),
+		},
+	}
+
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("\nExpected: %+v\n\nGot: %+v", expected, result)
+	}
+
+	if value, err := result[1].Value.Bool(); err != nil || value {
+		t.Fatalf("Expected logDebug to be false, got %v %v", value, err)
+	}
+	if enum := result[2].Enum(); len(enum) != 4 || enum[3] != (EnumValue{"3", "Make my CPU hurt"}) {
+		t.Fatalf("Unexpected enum values %+v", enum)
+	}
+}
+
+func TestGetPreferencesJSONSuccess(t *testing.T) {
+	resp := `{"MediaContainer": {"size": 1, "Setting": [{"id": "logDebug", "label": "Debug logging", "summary": "",
+		"type": "bool", "default": true, "value": false, "hidden": false, "advanced": true, "group": "general",
+		"enumValues": ""}]}}`
+
+	expectedReq := makeJSONServerRequest(t, "GET", "http://server.com:4040/:/prefs")
+	client = makeFakeClient(t, http.StatusOK, resp, expectedReq)
+
+	result, err := makeJSONTestServer().GetPreferences()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result) != 1 || result[0].Default != "true" || result[0].Value != "false" || !result[0].Advanced {
+		t.Fatalf("Unexpected settings %+v", result)
+	}
+}
+
+func TestSetPreferenceSuccess(t *testing.T) {
+	tests := []struct {
+		setting Setting
+		value   interface{}
+		query   string
+	}{
+		{Setting{ID: "logDebug", Type: "bool"}, true, "logDebug=true"},
+		{Setting{ID: "TranscoderQuality", Type: "int", EnumValues: "0:Automatic|3:Make my CPU hurt"}, 3, "TranscoderQuality=3"},
+		{Setting{ID: "TranscoderQuality", Type: "int", EnumValues: "0:Automatic|3:Make my CPU hurt"}, "0", "TranscoderQuality=0"},
+		{Setting{ID: "ratio", Type: "double"}, 1.5, "ratio=1.5"},
+		{Setting{ID: "FriendlyName", Type: "text"}, "New Name", "FriendlyName=New+Name"},
+	}
+
+	for _, test := range tests {
+		test.setting.Server = makeTestServer()
+		client = makeFakeClient(t, http.StatusOK, "", makeServerRequest(t, "PUT", "http://server.com:4040/:/prefs?"+test.query))
+
+		if err := test.setting.Set(test.value); err != nil {
+			t.Errorf("Setting %s to %v: %s", test.setting.ID, test.value, err)
+		}
+	}
+}
+
+func TestSetPreferenceInvalid(t *testing.T) {
+	tests := []struct {
+		setting Setting
+		value   interface{}
+	}{
+		{Setting{ID: "logDebug", Type: "bool"}, 1},
+		{Setting{ID: "logDebug", Type: "bool"}, "maybe"},
+		{Setting{ID: "TranscoderQuality", Type: "int"}, 1.5},
+		{Setting{ID: "TranscoderQuality", Type: "int"}, "high"},
+		{Setting{ID: "TranscoderQuality", Type: "int", EnumValues: "0:Automatic|3:Make my CPU hurt"}, 2},
+		{Setting{ID: "FriendlyName", Type: "text"}, []string{"name"}},
+	}
+
+	client = nil
+	for _, test := range tests {
+		if err := test.setting.Set(test.value); err == nil {
+			t.Errorf("Setting %s to %v should fail", test.setting.ID, test.value)
+		}
+	}
+}
+
+func TestSetPreferenceUnknownSetting(t *testing.T) {
+	client = makeFakeClient(t, http.StatusOK, prefsResp, makeServerRequest(t, "GET", "http://server.com:4040/:/prefs"))
+
+	if err := makeTestServer().SetPreference("NoSuchSetting", "1"); err == nil {
+		t.Fatal("Should err when the setting doesn't exist")
+	}
+}
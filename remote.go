@@ -0,0 +1,145 @@
+package plex
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Controller sends playback commands to a player, either proxied through a server or directly to the player.
+// A Controller is not safe for concurrent use.
+type Controller struct {
+	// The kind of media being controlled: video, music or photo. Defaults to video.
+	Type string
+
+	server    Server
+	player    Player
+	address   *HTTPURL
+	commandID int
+}
+
+// Control returns a Controller that sends commands to player through the server
+func (server Server) Control(player Player) *Controller {
+	return &Controller{Type: "video", server: server, player: player}
+}
+
+// ControlDirect returns a Controller that sends commands straight to the player at address. The server is still
+// needed to tell the player where to fetch media from.
+func (server Server) ControlDirect(player Player, address HTTPURL) *Controller {
+	return &Controller{Type: "video", server: server, player: player, address: &address}
+}
+
+// PlayMedia tells the player to start playing the queue from its selected item, offset from the start of the item
+func (c *Controller) PlayMedia(queue PlayQueue, offset time.Duration) error {
+	port := c.server.PublicAddress.Port()
+	if port == "" {
+		port = "32400"
+	}
+
+	params := url.Values{}
+	params.Set("machineIdentifier", c.server.ClientIdentifier)
+	params.Set("protocol", c.server.PublicAddress.Scheme)
+	params.Set("address", c.server.PublicAddress.Hostname())
+	params.Set("port", port)
+	params.Set("token", c.server.Owner.AuthToken)
+	params.Set("key", "/library/metadata/"+queue.SelectedMetadataItemID)
+	params.Set("containerKey", queue.containerKey())
+	params.Set("offset", strconv.FormatInt(int64(offset/time.Millisecond), 10))
+
+	return c.command("/player/playback/playMedia", params)
+}
+
+func (c *Controller) Play() error {
+	return c.command("/player/playback/play", nil)
+}
+
+func (c *Controller) Pause() error {
+	return c.command("/player/playback/pause", nil)
+}
+
+func (c *Controller) Stop() error {
+	return c.command("/player/playback/stop", nil)
+}
+
+func (c *Controller) SkipNext() error {
+	return c.command("/player/playback/skipNext", nil)
+}
+
+func (c *Controller) SkipPrevious() error {
+	return c.command("/player/playback/skipPrevious", nil)
+}
+
+func (c *Controller) SeekTo(offset time.Duration) error {
+	params := url.Values{}
+	params.Set("offset", strconv.FormatInt(int64(offset/time.Millisecond), 10))
+
+	return c.command("/player/playback/seekTo", params)
+}
+
+// SetVolume sets the player volume, from 0 to 100
+func (c *Controller) SetVolume(volume int) error {
+	if volume < 0 || volume > 100 {
+		return errors.New("Volume must be between 0 and 100, got " + strconv.Itoa(volume))
+	}
+
+	params := url.Values{}
+	params.Set("volume", strconv.Itoa(volume))
+
+	return c.command("/player/playback/setParameters", params)
+}
+
+func (c *Controller) SelectAudioStream(streamID int) error {
+	params := url.Values{}
+	params.Set("audioStreamID", strconv.Itoa(streamID))
+
+	return c.command("/player/playback/setStreams", params)
+}
+
+// SelectSubtitleStream switches subtitles to the given stream. A stream id of 0 turns subtitles off.
+func (c *Controller) SelectSubtitleStream(streamID int) error {
+	params := url.Values{}
+	params.Set("subtitleStreamID", strconv.Itoa(streamID))
+
+	return c.command("/player/playback/setStreams", params)
+}
+
+func (c *Controller) command(path string, params url.Values) error {
+	if params == nil {
+		params = url.Values{}
+	}
+	c.commandID++
+	params.Set("commandID", strconv.Itoa(c.commandID))
+	params.Set("type", c.Type)
+
+	req, err := c.newRequest(path, params)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("X-Plex-Target-Client-Identifier", c.player.MachineIdentifier)
+
+	// Commands are sent as GET but aren't idempotent, a player that acted on one before the connection failed
+	// would skip or seek twice
+	_, err = fetchContent(req, http.StatusOK, noRetries)
+	return err
+}
+
+func (c *Controller) newRequest(path string, params url.Values) (*http.Request, error) {
+	if c.address == nil {
+		return c.server.newRequest("GET", path, params, nil)
+	}
+
+	address := *c.address
+	address.Path = path
+	address.RawQuery = params.Encode()
+
+	req, err := http.NewRequest("GET", address.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("X-Plex-Client-Identifier", clientIdentifier)
+	req.Header.Add("X-Plex-Token", c.server.Owner.AuthToken)
+
+	return req, nil
+}
@@ -0,0 +1,111 @@
+package plex
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+var testPlayer = Player{MachineIdentifier: "playerID", Title: "Living Room"}
+
+func makeControlRequest(t *testing.T, rawurl string) *http.Request {
+	req := makeServerRequest(t, "GET", rawurl)
+	req.Header.Add("X-Plex-Target-Client-Identifier", "playerID")
+	return req
+}
+
+func TestControlThroughServer(t *testing.T) {
+	controller := makeTestServer().Control(testPlayer)
+
+	client = makeFakeClient(t, http.StatusOK, "", makeControlRequest(t,
+		"http://server.com:4040/player/playback/pause?commandID=1&type=video"))
+	if err := controller.Pause(); err != nil {
+		t.Fatal(err)
+	}
+
+	client = makeFakeClient(t, http.StatusOK, "", makeControlRequest(t,
+		"http://server.com:4040/player/playback/play?commandID=2&type=video"))
+	if err := controller.Play(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestControlDirect(t *testing.T) {
+	address := HTTPURL{url.URL{Scheme: "http", Host: "192.168.1.5:32500"}}
+	controller := makeTestServer().ControlDirect(testPlayer, address)
+
+	client = makeFakeClient(t, http.StatusOK, "", makeControlRequest(t,
+		"http://192.168.1.5:32500/player/playback/seekTo?commandID=1&offset=90000&type=video"))
+	if err := controller.SeekTo(90 * time.Second); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestControlPlayMedia(t *testing.T) {
+	controller := makeTestServer().Control(testPlayer)
+	queue := PlayQueue{ID: 42, SelectedMetadataItemID: "1751"}
+
+	client = makeFakeClient(t, http.StatusOK, "", makeControlRequest(t,
+		"http://server.com:4040/player/playback/playMedia?address=server.com&commandID=1&"+
+			"containerKey=%2FplayQueues%2F42%3Fown%3D1%26window%3D200&key=%2Flibrary%2Fmetadata%2F1751&"+
+			"machineIdentifier=machineID&offset=1500&port=4040&protocol=http&token=authToken&type=video"))
+	if err := controller.PlayMedia(queue, 1500*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestControlSelectStreams(t *testing.T) {
+	controller := makeTestServer().Control(testPlayer)
+
+	client = makeFakeClient(t, http.StatusOK, "", makeControlRequest(t,
+		"http://server.com:4040/player/playback/setStreams?audioStreamID=10813&commandID=1&type=video"))
+	if err := controller.SelectAudioStream(10813); err != nil {
+		t.Fatal(err)
+	}
+
+	client = makeFakeClient(t, http.StatusOK, "", makeControlRequest(t,
+		"http://server.com:4040/player/playback/setStreams?commandID=2&subtitleStreamID=0&type=video"))
+	if err := controller.SelectSubtitleStream(0); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestControlSetVolume(t *testing.T) {
+	controller := makeTestServer().Control(testPlayer)
+
+	if err := controller.SetVolume(101); err == nil {
+		t.Fatal("Should err when volume is out of range")
+	}
+
+	client = makeFakeClient(t, http.StatusOK, "", makeControlRequest(t,
+		"http://server.com:4040/player/playback/setParameters?commandID=1&type=video&volume=40"))
+	if err := controller.SetVolume(40); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestControlFail(t *testing.T) {
+	controller := makeTestServer().Control(testPlayer)
+
+	client = makeFakeClient(t, http.StatusNotFound, "", makeControlRequest(t,
+		"http://server.com:4040/player/playback/stop?commandID=1&type=video"))
+	if err := controller.Stop(); err == nil {
+		t.Fatal("Stop returned success when it received bad status code")
+	}
+}
+
+func TestControlNotRetried(t *testing.T) {
+	_, restore := recordWaits()
+	defer restore()
+
+	testServer, server, attempts := startFlakyServer(t, withStatus(http.StatusServiceUnavailable))
+	defer testServer.Close()
+
+	if err := server.Control(testPlayer).SkipNext(); err == nil {
+		t.Fatal("SkipNext returned success when it received bad status code")
+	}
+	if *attempts != 1 {
+		t.Fatalf("Expected the command to be sent once, sent %d times", *attempts)
+	}
+}
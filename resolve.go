@@ -0,0 +1,24 @@
+package plex
+
+// Resolve returns the absolute URL of path on the server. Paths that are already absolute, such as thumbnails
+// hosted by an agent, are returned unchanged. The zero URLPath resolves to the zero HTTPURL.
+func (server Server) Resolve(path URLPath) HTTPURL {
+	if path == (URLPath{}) {
+		return HTTPURL{}
+	}
+	return HTTPURL{*server.PublicAddress.ResolveReference(&path.URL)}
+}
+
+// ResolveWithToken is Resolve with the owner's token added to URLs on the server, so they can be fetched by
+// clients that can't set headers
+func (server Server) ResolveWithToken(path URLPath) HTTPURL {
+	address := server.Resolve(path)
+	if address.Host != server.PublicAddress.Host {
+		return address
+	}
+
+	query := address.Query()
+	query.Set("X-Plex-Token", server.Owner.AuthToken)
+	address.RawQuery = query.Encode()
+	return address
+}
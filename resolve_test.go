@@ -0,0 +1,47 @@
+package plex
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	server := makeTestServer()
+
+	tests := []struct {
+		path      string
+		expected  string
+		withToken string
+	}{
+		{"/library/metadata/1751/thumb/1430373196",
+			"http://server.com:4040/library/metadata/1751/thumb/1430373196",
+			"http://server.com:4040/library/metadata/1751/thumb/1430373196?X-Plex-Token=authToken"},
+		{"/library/parts/2147/file.mkv?download=1",
+			"http://server.com:4040/library/parts/2147/file.mkv?download=1",
+			"http://server.com:4040/library/parts/2147/file.mkv?X-Plex-Token=authToken&download=1"},
+		{"https://image.tmdb.org/t/p/original/poster.jpg",
+			"https://image.tmdb.org/t/p/original/poster.jpg",
+			"https://image.tmdb.org/t/p/original/poster.jpg"},
+	}
+
+	for _, test := range tests {
+		parsed, err := url.Parse(test.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := URLPath{*parsed}
+
+		if result := server.Resolve(path); result.String() != test.expected {
+			t.Errorf("Resolve(%s)\nExpected: %s\nGot: %s", test.path, test.expected, result)
+		}
+		if result := server.ResolveWithToken(path); result.String() != test.withToken {
+			t.Errorf("ResolveWithToken(%s)\nExpected: %s\nGot: %s", test.path, test.withToken, result)
+		}
+	}
+}
+
+func TestResolveEmpty(t *testing.T) {
+	if result := makeTestServer().ResolveWithToken(URLPath{}); result != (HTTPURL{}) {
+		t.Fatalf("Expected the empty path to resolve to nothing, got %s", result)
+	}
+}
@@ -0,0 +1,123 @@
+package plex
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how requests that fail with a transient error are retried. Only requests with idempotent
+// methods are retried, after a network error or a 429, 502, 503 or 504 status. It is set per User and Server,
+// the zero value uses the default policy of 4 attempts with backoff starting at 500ms.
+type RetryPolicy struct {
+	// Maximum number of attempts including the first. 1 disables retries.
+	MaxAttempts int
+	// Delay before the first retry, doubled for each retry after it. The actual delay is randomly jittered
+	// between half and all of this.
+	InitialBackoff time.Duration
+	// Upper bound on the delay between attempts, before jitter
+	MaxBackoff time.Duration
+	// Requests are not retried once this much time has passed since the first attempt. Zero means no limit.
+	MaxElapsedTime time.Duration
+}
+
+var defaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    4,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     10 * time.Second,
+	MaxElapsedTime: 30 * time.Second,
+}
+
+// noRetries is for requests that aren't safe to repeat whatever their method, such as player commands
+var noRetries = RetryPolicy{MaxAttempts: 1}
+
+func (policy RetryPolicy) orDefault() RetryPolicy {
+	if policy == (RetryPolicy{}) {
+		return defaultRetryPolicy
+	}
+	return policy
+}
+
+// Hook to override for tests
+var wait = func(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+var idempotentMethods = map[string]bool{
+	"GET":     true,
+	"HEAD":    true,
+	"PUT":     true,
+	"DELETE":  true,
+	"OPTIONS": true,
+}
+
+var retryableStatuses = map[int]bool{
+	http.StatusTooManyRequests:    true,
+	http.StatusBadGateway:         true,
+	http.StatusServiceUnavailable: true,
+	http.StatusGatewayTimeout:     true,
+}
+
+// delay returns how long to wait before the next attempt, or false if the request should not be retried.
+// resp is nil when the attempt failed with a network error.
+func (policy RetryPolicy) delay(req *http.Request, resp *http.Response, attempt int, elapsed time.Duration) (time.Duration, bool) {
+	if attempt >= policy.MaxAttempts || !idempotentMethods[req.Method] || req.Context().Err() != nil {
+		return 0, false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return 0, false
+	}
+	if resp != nil && !retryableStatuses[resp.StatusCode] {
+		return 0, false
+	}
+
+	delay, ok := retryAfter(resp)
+	if !ok {
+		backoff := policy.InitialBackoff << uint(attempt-1)
+		if backoff > policy.MaxBackoff || backoff <= 0 {
+			backoff = policy.MaxBackoff
+		}
+		delay = backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+	}
+
+	if policy.MaxElapsedTime > 0 && elapsed+delay > policy.MaxElapsedTime {
+		return 0, false
+	}
+	return delay, true
+}
+
+// retryAfter reads the Retry-After header, which is either a number of seconds or a date
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+
+	header := resp.Header.Get("Retry-After")
+	if header == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(header); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+
+	return 0, false
+}
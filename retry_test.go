@@ -0,0 +1,173 @@
+package plex
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// startFlakyServer answers the first len(failures) requests by calling the matching failure, then succeeds with
+// an empty MediaContainer
+func startFlakyServer(t *testing.T, failures ...func(http.ResponseWriter)) (*httptest.Server, Server, *int) {
+	attempts := 0
+	testServer, server := startTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts <= len(failures) {
+			failures[attempts-1](w)
+			return
+		}
+		w.Write([]byte(`<MediaContainer size="0"></MediaContainer>`))
+	})
+	server.Retry = testRetryPolicy
+	return testServer, server, &attempts
+}
+
+func withStatus(status int, header ...string) func(http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		for i := 0; i+1 < len(header); i += 2 {
+			w.Header().Set(header[i], header[i+1])
+		}
+		w.WriteHeader(status)
+	}
+}
+
+func withConnectionReset(w http.ResponseWriter) {
+	conn, _, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		panic(err)
+	}
+	conn.Close()
+}
+
+// recordWaits replaces the retry delay with one that returns immediately and records how long it would have waited
+func recordWaits() (*[]time.Duration, func()) {
+	var delays []time.Duration
+	originalWait := wait
+
+	wait = func(ctx context.Context, delay time.Duration) error {
+		delays = append(delays, delay)
+		return nil
+	}
+
+	return &delays, func() {
+		wait = originalWait
+	}
+}
+
+var testRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     time.Second,
+	MaxElapsedTime: 30 * time.Second,
+}
+
+func TestRetryTransientFailures(t *testing.T) {
+	delays, restore := recordWaits()
+	defer restore()
+
+	testServer, server, attempts := startFlakyServer(t, withConnectionReset, withStatus(http.StatusServiceUnavailable))
+	defer testServer.Close()
+
+	if _, err := server.GetActivity(); err != nil {
+		t.Fatal(err)
+	}
+
+	if *attempts != 3 {
+		t.Fatalf("Expected 3 attempts, got %d", *attempts)
+	}
+	if len(*delays) != 2 {
+		t.Fatalf("Expected 2 waits, got %v", *delays)
+	}
+	for i, delay := range *delays {
+		backoff := testRetryPolicy.InitialBackoff << uint(i)
+		if delay < backoff/2 || delay > backoff {
+			t.Errorf("Delay %d was %s, expected between %s and %s", i, delay, backoff/2, backoff)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	delays, restore := recordWaits()
+	defer restore()
+
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	testServer, server, _ := startFlakyServer(t,
+		withStatus(http.StatusTooManyRequests, "Retry-After", "7"),
+		withStatus(http.StatusServiceUnavailable, "Retry-After", date))
+	defer testServer.Close()
+
+	server.Retry.MaxElapsedTime = 0
+	if _, err := server.GetActivity(); err != nil {
+		t.Fatal(err)
+	}
+
+	if (*delays)[0] != 7*time.Second {
+		t.Fatalf("Expected to wait 7s, waited %s", (*delays)[0])
+	}
+	if (*delays)[1] < 58*time.Second || (*delays)[1] > time.Minute {
+		t.Fatalf("Expected to wait about a minute, waited %s", (*delays)[1])
+	}
+}
+
+func TestRetryMaxElapsedTime(t *testing.T) {
+	_, restore := recordWaits()
+	defer restore()
+
+	testServer, server, attempts := startFlakyServer(t, withStatus(http.StatusServiceUnavailable, "Retry-After", "60"))
+	defer testServer.Close()
+
+	if _, err := server.GetActivity(); err == nil {
+		t.Fatal("Should err when the server asks to retry after the max elapsed time")
+	}
+	if *attempts != 1 {
+		t.Fatalf("Expected 1 attempt, got %d", *attempts)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	_, restore := recordWaits()
+	defer restore()
+
+	unavailable := withStatus(http.StatusServiceUnavailable)
+	testServer, server, attempts := startFlakyServer(t, unavailable, unavailable, unavailable, unavailable)
+	defer testServer.Close()
+
+	if _, err := server.GetActivity(); err == nil {
+		t.Fatal("Should err when every attempt fails")
+	}
+	if *attempts != 3 {
+		t.Fatalf("Expected 3 attempts, got %d", *attempts)
+	}
+}
+
+func TestRetryOnlyTransientFailures(t *testing.T) {
+	_, restore := recordWaits()
+	defer restore()
+
+	testServer, server, attempts := startFlakyServer(t, withStatus(http.StatusUnauthorized))
+	defer testServer.Close()
+
+	if _, err := server.GetActivity(); err == nil {
+		t.Fatal("Should err when server returns 401")
+	}
+	if *attempts != 1 {
+		t.Fatalf("Expected 1 attempt, got %d", *attempts)
+	}
+}
+
+func TestRetryOnlyIdempotentMethods(t *testing.T) {
+	_, restore := recordWaits()
+	defer restore()
+
+	testServer, server, attempts := startFlakyServer(t, withStatus(http.StatusServiceUnavailable))
+	defer testServer.Close()
+
+	if _, err := server.CreatePlayQueue("1", PlayQueueOptions{}); err == nil {
+		t.Fatal("Should err when a POST fails")
+	}
+	if *attempts != 1 {
+		t.Fatalf("Expected 1 attempt, got %d", *attempts)
+	}
+}
@@ -0,0 +1,119 @@
+package plex
+
+import (
+	"encoding/xml"
+	"net/url"
+	"strconv"
+)
+
+// StatsTimespan is the interval between the samples the server returns
+type StatsTimespan int
+
+const (
+	TimespanMonths  StatsTimespan = 1
+	TimespanWeeks   StatsTimespan = 2
+	TimespanDays    StatsTimespan = 3
+	TimespanHours   StatsTimespan = 4
+	TimespanSeconds StatsTimespan = 6
+)
+
+type bandwidthResp struct {
+	XMLName  xml.Name          `xml:"MediaContainer" json:"-"`
+	Accounts []StatsAccount    `xml:"Account" json:"Account"`
+	Devices  []StatsDevice     `xml:"Device" json:"Device"`
+	Samples  []BandwidthSample `xml:"StatisticsBandwidth" json:"StatisticsBandwidth"`
+}
+
+type resourcesResp struct {
+	XMLName xml.Name         `xml:"MediaContainer" json:"-"`
+	Samples []ResourceSample `xml:"StatisticsResources" json:"StatisticsResources"`
+}
+
+// StatsAccount is an account that used the server during the statistics period
+type StatsAccount struct {
+	ID   int64  `xml:"id,attr" json:"id"`
+	Name string `xml:"name,attr" json:"name"`
+}
+
+// StatsDevice is a device that used the server during the statistics period
+type StatsDevice struct {
+	ID               int64    `xml:"id,attr" json:"id"`
+	Name             string   `xml:"name,attr" json:"name"`
+	Platform         string   `xml:"platform,attr" json:"platform"`
+	ClientIdentifier string   `xml:"clientIdentifier,attr" json:"clientIdentifier"`
+	CreatedAt        UnixTime `xml:"createdAt,attr" json:"createdAt"`
+}
+
+// BandwidthSample is the data sent to one device of one account during the timespan ending At
+type BandwidthSample struct {
+	AccountID int64         `xml:"accountID,attr" json:"accountID"`
+	DeviceID  int64         `xml:"deviceID,attr" json:"deviceID"`
+	Timespan  StatsTimespan `xml:"timespan,attr" json:"timespan"`
+	At        UnixTime      `xml:"at,attr" json:"at"`
+	LAN       IntAsBool     `xml:"lan,attr" json:"lan"`
+	Bytes     int64         `xml:"bytes,attr" json:"bytes"`
+	Account   StatsAccount  `xml:"-" json:"-"`
+	Device    StatsDevice   `xml:"-" json:"-"`
+}
+
+// ResourceSample is the server's CPU and memory use, in percent, during the timespan ending At
+type ResourceSample struct {
+	Timespan                 StatsTimespan `xml:"timespan,attr" json:"timespan"`
+	At                       UnixTime      `xml:"at,attr" json:"at"`
+	HostCPUUtilization       float64       `xml:"hostCpuUtilization,attr" json:"hostCpuUtilization"`
+	ProcessCPUUtilization    float64       `xml:"processCpuUtilization,attr" json:"processCpuUtilization"`
+	HostMemoryUtilization    float64       `xml:"hostMemoryUtilization,attr" json:"hostMemoryUtilization"`
+	ProcessMemoryUtilization float64       `xml:"processMemoryUtilization,attr" json:"processMemoryUtilization"`
+}
+
+// BandwidthStats returns the data the server has sent, one sample per account, device, network and timespan
+func (server Server) BandwidthStats(timespan StatsTimespan) ([]BandwidthSample, error) {
+	container := &bandwidthResp{}
+	if err := server.fetch("GET", "/statistics/bandwidth", timespanParams(timespan), container); err != nil {
+		return nil, err
+	}
+
+	accounts := map[int64]StatsAccount{}
+	for _, account := range container.Accounts {
+		accounts[account.ID] = account
+	}
+	devices := map[int64]StatsDevice{}
+	for _, device := range container.Devices {
+		devices[device.ID] = device
+	}
+
+	for i, sample := range container.Samples {
+		container.Samples[i].Account = accounts[sample.AccountID]
+		container.Samples[i].Device = devices[sample.DeviceID]
+	}
+
+	return container.Samples, nil
+}
+
+// ResourceStats returns the server's recent CPU and memory use
+func (server Server) ResourceStats() ([]ResourceSample, error) {
+	container := &resourcesResp{}
+	if err := server.fetch("GET", "/statistics/resources", timespanParams(TimespanSeconds), container); err != nil {
+		return nil, err
+	}
+
+	return container.Samples, nil
+}
+
+// TotalBytes adds up the bytes sent on the local network and to remote clients
+func TotalBytes(samples []BandwidthSample) (lan, wan int64) {
+	for _, sample := range samples {
+		if sample.LAN {
+			lan += sample.Bytes
+		} else {
+			wan += sample.Bytes
+		}
+	}
+	return lan, wan
+}
+
+func timespanParams(timespan StatsTimespan) url.Values {
+	params := url.Values{}
+	params.Set("timespan", strconv.Itoa(int(timespan)))
+	return params
+}
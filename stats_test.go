@@ -0,0 +1,108 @@
+package plex
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBandwidthStatsSuccess(t *testing.T) {
+	resp := `<?xml version="1.0" encoding="UTF-8"?>
+	<MediaContainer size="5">
+	  <Device id="7" name="My Nexus 7" platform="Android" clientIdentifier="5418fbf4404066f0-com-plexapp-android" createdAt="1430373171" />
+	  <Account id="1" key="/accounts/1" name="owner" defaultAudioLanguage="en" autoSelectAudio="1" />
+	  <Account id="22" key="/accounts/22" name="friend" />
+	  <StatisticsBandwidth accountID="1" deviceID="7" timespan="6" at="1430373196" lan="1" bytes="2000" />
+	  <StatisticsBandwidth accountID="22" deviceID="7" timespan="6" at="1430373196" lan="0" bytes="500" />
+	</MediaContainer>`
+
+	client = makeFakeClient(t, http.StatusOK, resp, makeServerRequest(t, "GET", "http://server.com:4040/statistics/bandwidth?timespan=6"))
+
+	result, err := makeTestServer().BandwidthStats(TimespanSeconds)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	device := StatsDevice{
+		ID:               7,
+		Name:             "My Nexus 7",
+		Platform:         "Android",
+		ClientIdentifier: "5418fbf4404066f0-com-plexapp-android",
+		CreatedAt:        UnixTime{time.Unix(1430373171, 0)},
+	}
+	expected := []BandwidthSample{
+		BandwidthSample{
+			AccountID: 1,
+			DeviceID:  7,
+			Timespan:  TimespanSeconds,
+			At:        UnixTime{time.Unix(1430373196, 0)},
+			LAN:       true,
+			Bytes:     2000,
+			Account:   StatsAccount{ID: 1, Name: "owner"},
+			Device:    device,
+		},
+		BandwidthSample{
+			AccountID: 22,
+			DeviceID:  7,
+			Timespan:  TimespanSeconds,
+			At:        UnixTime{time.Unix(1430373196, 0)},
+			Bytes:     500,
+			Account:   StatsAccount{ID: 22, Name: "friend"},
+			Device:    device,
+		},
+	}
+
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("\nExpected: %+v\n\nGot: %+v", expected, result)
+	}
+
+	if lan, wan := TotalBytes(result); lan != 2000 || wan != 500 {
+		t.Fatalf("Unexpected totals lan %d wan %d", lan, wan)
+	}
+}
+
+func TestBandwidthStatsFail(t *testing.T) {
+	client = makeFakeClient(t, http.StatusUnauthorized, "", makeServerRequest(t, "GET", "http://server.com:4040/statistics/bandwidth?timespan=4"))
+
+	if _, err := makeTestServer().BandwidthStats(TimespanHours); err == nil {
+		t.Fatal("BandwidthStats returned success when it received bad status code")
+	}
+}
+
+func TestResourceStatsJSONSuccess(t *testing.T) {
+	resp := `{"MediaContainer": {"size": 1, "StatisticsResources": [{"timespan": 6, "at": 1430373196,
+		"hostCpuUtilization": 12.5, "processCpuUtilization": 3.25, "hostMemoryUtilization": 40.5,
+		"processMemoryUtilization": 2.75}]}}`
+
+	expectedReq := makeJSONServerRequest(t, "GET", "http://server.com:4040/statistics/resources?timespan=6")
+	client = makeFakeClient(t, http.StatusOK, resp, expectedReq)
+
+	result, err := makeJSONTestServer().ResourceStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ResourceSample{
+		ResourceSample{
+			Timespan:                 TimespanSeconds,
+			At:                       UnixTime{time.Unix(1430373196, 0)},
+			HostCPUUtilization:       12.5,
+			ProcessCPUUtilization:    3.25,
+			HostMemoryUtilization:    40.5,
+			ProcessMemoryUtilization: 2.75,
+		},
+	}
+
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("\nExpected: %+v\n\nGot: %+v", expected, result)
+	}
+}
+
+func TestResourceStatsFail(t *testing.T) {
+	client = makeFakeClient(t, http.StatusUnauthorized, "", makeServerRequest(t, "GET", "http://server.com:4040/statistics/resources?timespan=6"))
+
+	if _, err := makeTestServer().ResourceStats(); err == nil {
+		t.Fatal("ResourceStats returned success when it received bad status code")
+	}
+}
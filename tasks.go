@@ -0,0 +1,152 @@
+package plex
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"time"
+)
+
+type activitiesResp struct {
+	XMLName    xml.Name   `xml:"MediaContainer" json:"-"`
+	Activities []Activity `xml:"Activity" json:"Activity"`
+}
+
+// Activity is a background task the server is running, such as a library scan
+type Activity struct {
+	UUID        string          `xml:"uuid,attr" json:"uuid"`
+	Type        string          `xml:"type,attr" json:"type"`
+	Title       string          `xml:"title,attr" json:"title"`
+	Subtitle    string          `xml:"subtitle,attr" json:"subtitle"`
+	Progress    int             `xml:"progress,attr" json:"progress"`
+	Cancellable IntAsBool       `xml:"cancellable,attr" json:"cancellable"`
+	UserID      int64           `xml:"userID,attr" json:"userID"`
+	Context     ActivityContext `xml:"Context" json:"Context"`
+}
+
+type ActivityContext struct {
+	LibrarySectionID ID `xml:"librarySectionID,attr" json:"librarySectionID"`
+}
+
+type butlerTasksResp struct {
+	XMLName xml.Name     `xml:"ButlerTasks"`
+	Tasks   []ButlerTask `xml:"ButlerTask"`
+}
+
+// ButlerTask is a maintenance task the server runs on a schedule, such as BackupDatabase or CleanOldBundles
+type ButlerTask struct {
+	Name               string    `xml:"name,attr"`
+	Title              string    `xml:"title,attr"`
+	Description        string    `xml:"description,attr"`
+	Enabled            IntAsBool `xml:"enabled,attr"`
+	Interval           int       `xml:"interval,attr"`
+	ScheduleRandomized IntAsBool `xml:"scheduleRandomized,attr"`
+	Server             Server    `xml:"-"`
+}
+
+// Hooks to override for tests
+var activityPollInterval = time.Second
+var activityStartTimeout = 5 * time.Second
+
+func (server Server) GetActivities() ([]Activity, error) {
+	return server.getActivities(context.Background())
+}
+
+func (server Server) getActivities(ctx context.Context) ([]Activity, error) {
+	container := &activitiesResp{}
+	if err := server.fetchContext(ctx, "GET", "/activities", nil, container); err != nil {
+		return nil, err
+	}
+
+	return container.Activities, nil
+}
+
+// CancelActivity stops a running activity. Only activities that are Cancellable can be stopped.
+func (server Server) CancelActivity(activity Activity) error {
+	if !activity.Cancellable {
+		return errors.New("Activity " + activity.UUID + " can't be cancelled")
+	}
+	return server.fetch("DELETE", "/activities/"+activity.UUID, nil, nil)
+}
+
+// WaitForActivities blocks until the activities that match have finished. The server can take a moment to start
+// an activity after the request that triggered it, so if no matching activity is running it keeps checking for a
+// few seconds before returning.
+func (server Server) WaitForActivities(ctx context.Context, match func(Activity) bool) error {
+	started := time.Now()
+	seen := false
+
+	for {
+		activities, err := server.getActivities(ctx)
+		if err != nil {
+			return err
+		}
+
+		running := false
+		for _, activity := range activities {
+			if match(activity) {
+				running = true
+				break
+			}
+		}
+
+		seen = seen || running
+		if !running && (seen || time.Since(started) >= activityStartTimeout) {
+			return nil
+		}
+
+		if err := wait(ctx, activityPollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+// ActivitiesOfType matches activities of the given type, e.g. library.update.section or library.refresh.items
+func ActivitiesOfType(activityType string) func(Activity) bool {
+	return func(activity Activity) bool {
+		return activity.Type == activityType
+	}
+}
+
+// ActivitiesForSection matches activities working on the section with the given key
+func ActivitiesForSection(key string) func(Activity) bool {
+	return func(activity Activity) bool {
+		return string(activity.Context.LibrarySectionID) == key
+	}
+}
+
+func (server Server) GetButlerTasks() ([]ButlerTask, error) {
+	req, err := server.newRequest("GET", "/butler", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	// The butler's response isn't wrapped in a MediaContainer, so always ask for XML
+	req.Header.Del("Accept")
+
+	content, err := fetchContent(req, http.StatusOK, server.Retry)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &butlerTasksResp{}
+	if err := xml.Unmarshal(content, resp); err != nil {
+		return nil, err
+	}
+
+	for i := range resp.Tasks {
+		resp.Tasks[i].Server = server
+	}
+
+	return resp.Tasks, nil
+}
+
+// Start runs the task now, outside of its schedule
+func (task ButlerTask) Start() error {
+	return task.Server.fetch("POST", "/butler/"+task.Name, nil, nil)
+}
+
+// Stop cancels the task if it is running
+func (task ButlerTask) Stop() error {
+	return task.Server.fetch("DELETE", "/butler/"+task.Name, nil, nil)
+}
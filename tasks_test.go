@@ -0,0 +1,218 @@
+package plex
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetActivitiesSuccess(t *testing.T) {
+	resp := `<?xml version="1.0" encoding="UTF-8"?>
+	<MediaContainer size="2">
+	  <Activity uuid="uuid-1" type="library.update.section" cancellable="0" userID="1" title="Scanning Movies" subtitle="New Movie" progress="42">
+	    <Context librarySectionID="1" />
+	  </Activity>
+	  <Activity uuid="uuid-2" type="media.generate.bif" cancellable="1" userID="1" title="Generating thumbnails" subtitle="" progress="3" />
+	</MediaContainer>`
+
+	client = makeFakeClient(t, http.StatusOK, resp, makeServerRequest(t, "GET", "http://server.com:4040/activities"))
+
+	result, err := makeTestServer().GetActivities()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Activity{
+		Activity{
+			UUID:     "uuid-1",
+			Type:     "library.update.section",
+			Title:    "Scanning Movies",
+			Subtitle: "New Movie",
+			Progress: 42,
+			UserID:   1,
+			Context:  ActivityContext{LibrarySectionID: "1"},
+		},
+		Activity{
+			UUID:        "uuid-2",
+			Type:        "media.generate.bif",
+			Title:       "Generating thumbnails",
+			Progress:    3,
+			Cancellable: true,
+			UserID:      1,
+		},
+	}
+
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("\nExpected: %+v\n\nGot: %+v", expected, result)
+	}
+}
+
+func TestGetActivitiesFail(t *testing.T) {
+	client = makeFakeClient(t, http.StatusUnauthorized, "", makeServerRequest(t, "GET", "http://server.com:4040/activities"))
+
+	if _, err := makeTestServer().GetActivities(); err == nil {
+		t.Fatal("GetActivities returned success when it received bad status code")
+	}
+}
+
+// startActivitiesServer answers each poll of /activities with the next of responses, repeating the last
+func startActivitiesServer(t *testing.T, responses ...string) (*httptest.Server, Server, *int) {
+	polls := 0
+	testServer, server := startTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		resp := responses[len(responses)-1]
+		if polls < len(responses) {
+			resp = responses[polls]
+		}
+		polls++
+		w.Write([]byte(resp))
+	})
+	return testServer, server, &polls
+}
+
+const noActivities = `<MediaContainer size="0"></MediaContainer>`
+const scanningSection = `<MediaContainer size="2">
+	<Activity uuid="uuid-1" type="library.update.section" progress="42"><Context librarySectionID="1" /></Activity>
+	<Activity uuid="uuid-2" type="library.update.section" progress="10"><Context librarySectionID="2" /></Activity>
+</MediaContainer>`
+const scanningOtherSection = `<MediaContainer size="1">
+	<Activity uuid="uuid-2" type="library.update.section" progress="50"><Context librarySectionID="2" /></Activity>
+</MediaContainer>`
+
+func TestWaitForScan(t *testing.T) {
+	_, restore := recordWaits()
+	defer restore()
+
+	testServer, server, polls := startActivitiesServer(t, noActivities, scanningSection, scanningSection, scanningOtherSection)
+	defer testServer.Close()
+
+	section := Section{Key: "1", Server: server}
+	if err := section.WaitForScan(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if *polls != 4 {
+		t.Fatalf("Expected to poll until the scan finished, polled %d times", *polls)
+	}
+}
+
+func TestWaitForActivitiesNeverStarted(t *testing.T) {
+	_, restore := recordWaits()
+	defer restore()
+	activityStartTimeout = 10 * time.Millisecond
+	defer func() { activityStartTimeout = 5 * time.Second }()
+
+	testServer, server, _ := startActivitiesServer(t, noActivities)
+	defer testServer.Close()
+
+	done := make(chan error)
+	go func() {
+		done <- server.WaitForActivities(context.Background(), ActivitiesOfType("library.update.section"))
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Should stop waiting when the activity never starts")
+	}
+}
+
+func TestWaitForActivitiesCanceled(t *testing.T) {
+	testServer, server, _ := startActivitiesServer(t, scanningSection)
+	defer testServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := server.WaitForActivities(ctx, ActivitiesForSection("1")); err == nil {
+		t.Fatal("Should err when the context is done before the activity finishes")
+	}
+}
+
+func TestCancelActivitySuccess(t *testing.T) {
+	client = makeFakeClient(t, http.StatusOK, "", makeServerRequest(t, "DELETE", "http://server.com:4040/activities/uuid-2"))
+
+	activity := Activity{UUID: "uuid-2", Cancellable: true}
+	if err := makeTestServer().CancelActivity(activity); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCancelActivityNotCancellable(t *testing.T) {
+	client = makeFakeClient(t, http.StatusOK, "", nil)
+
+	if err := makeTestServer().CancelActivity(Activity{UUID: "uuid-1"}); err == nil {
+		t.Fatal("Should err when the activity can't be cancelled")
+	}
+}
+
+func TestGetButlerTasksSuccess(t *testing.T) {
+	resp := `<?xml version="1.0" encoding="UTF-8"?>
+	<ButlerTasks>
+	  <ButlerTask name="BackupDatabase" interval="3" scheduleRandomized="0" enabled="1" title="Backup Database" description="Create a backup copy of the server's database in the configured backup directory" />
+	  <ButlerTask name="CleanOldBundles" interval="7" scheduleRandomized="1" enabled="0" title="Remove Old Bundles" description="Remove old bundles" />
+	</ButlerTasks>`
+
+	// Ask for XML even when the server's Format is JSON
+	client = makeFakeClient(t, http.StatusOK, resp, makeServerRequest(t, "GET", "http://server.com:4040/butler"))
+
+	server := makeJSONTestServer()
+	result, err := server.GetButlerTasks()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ButlerTask{
+		ButlerTask{
+			Name:        "BackupDatabase",
+			Title:       "Backup Database",
+			Description: "Create a backup copy of the server's database in the configured backup directory",
+			Enabled:     true,
+			Interval:    3,
+			Server:      server,
+		},
+		ButlerTask{
+			Name:               "CleanOldBundles",
+			Title:              "Remove Old Bundles",
+			Description:        "Remove old bundles",
+			Interval:           7,
+			ScheduleRandomized: true,
+			Server:             server,
+		},
+	}
+
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("\nExpected: %+v\n\nGot: %+v", expected, result)
+	}
+}
+
+func TestGetButlerTasksFail(t *testing.T) {
+	client = makeFakeClient(t, http.StatusUnauthorized, "", makeServerRequest(t, "GET", "http://server.com:4040/butler"))
+
+	if _, err := makeTestServer().GetButlerTasks(); err == nil {
+		t.Fatal("GetButlerTasks returned success when it received bad status code")
+	}
+}
+
+func TestStartButlerTask(t *testing.T) {
+	client = makeFakeClient(t, http.StatusOK, "", makeServerRequest(t, "POST", "http://server.com:4040/butler/BackupDatabase"))
+
+	task := ButlerTask{Name: "BackupDatabase", Server: makeTestServer()}
+	if err := task.Start(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStopButlerTask(t *testing.T) {
+	client = makeFakeClient(t, http.StatusOK, "", makeServerRequest(t, "DELETE", "http://server.com:4040/butler/BackupDatabase"))
+
+	task := ButlerTask{Name: "BackupDatabase", Server: makeTestServer()}
+	if err := task.Stop(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,181 @@
+package plex
+
+import (
+	"encoding/xml"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type StreamProtocol string
+
+const (
+	ProtocolHLS  StreamProtocol = "hls"
+	ProtocolDASH StreamProtocol = "dash"
+)
+
+// TranscodeOptions describe the stream a player wants. The zero value asks for an HLS stream the server may
+// direct play or direct stream if the player can handle the original.
+type TranscodeOptions struct {
+	Protocol StreamProtocol
+	// DisableDirectPlay stops the server sending the original file, DisableDirectStream also stops it copying
+	// streams it could otherwise remux into the new container
+	DisableDirectPlay   bool
+	DisableDirectStream bool
+	// MaxVideoBitrate is in kbps
+	MaxVideoBitrate int
+	// VideoResolution is the largest resolution the player wants, e.g. 1280x720
+	VideoResolution  string
+	AudioStreamID    int64
+	SubtitleStreamID int64
+	// BurnSubtitles renders the selected subtitles into the video instead of sending them separately
+	BurnSubtitles bool
+	Offset        time.Duration
+	// SessionID identifies the transcode session so it can be stopped or resumed. Each playback should use its own.
+	SessionID string
+	// Platform and Product name the player, the server picks the profile of what it can play from them. They
+	// default to Generic and this library.
+	Platform string
+	Product  string
+	// ClientProfileExtra adds to the profile the server uses to decide what the player supports, e.g.
+	// add-limitation(scope=videoCodec&scopeName=h264&type=upperBound&name=video.level&value=41)
+	ClientProfileExtra []string
+}
+
+type transcodeDecisionResp struct {
+	XMLName        xml.Name `xml:"MediaContainer" json:"-"`
+	GeneralCode    int      `xml:"generalDecisionCode,attr" json:"generalDecisionCode"`
+	GeneralText    string   `xml:"generalDecisionText,attr" json:"generalDecisionText"`
+	DirectPlayCode int      `xml:"directPlayDecisionCode,attr" json:"directPlayDecisionCode"`
+	DirectPlayText string   `xml:"directPlayDecisionText,attr" json:"directPlayDecisionText"`
+	TranscodeCode  int      `xml:"transcodeDecisionCode,attr" json:"transcodeDecisionCode"`
+	TranscodeText  string   `xml:"transcodeDecisionText,attr" json:"transcodeDecisionText"`
+	Items          []Video  `xml:",any" json:"Metadata"`
+}
+
+// TranscodeDecision is what the server would do to stream an item to a player. Codes of 1000 mean OK, the text
+// explains other codes.
+type TranscodeDecision struct {
+	GeneralCode    int
+	GeneralText    string
+	DirectPlayCode int
+	DirectPlayText string
+	TranscodeCode  int
+	TranscodeText  string
+	// Item is the item as it would be streamed, the Decision of its Part and Streams say how each is handled
+	Item Video
+}
+
+const decisionOK = 1000
+
+// DirectPlay reports whether the player would play the original file
+func (decision TranscodeDecision) DirectPlay() bool {
+	return decision.DirectPlayCode == decisionOK
+}
+
+// TranscodeURL returns the URL of a stream of the item with the given rating key, a playlist for HLS or a
+// manifest for DASH. The URL includes the owner's token so it can be handed straight to a player.
+func (server Server) TranscodeURL(ratingKey string, opts TranscodeOptions) HTTPURL {
+	params := opts.params(ratingKey)
+	params.Set("X-Plex-Token", server.Owner.AuthToken)
+
+	address := server.PublicAddress
+	address.Path = "/video/:/transcode/universal/start." + opts.extension()
+	address.RawQuery = params.Encode()
+	return address
+}
+
+// TranscodeDecision asks the server how it would stream the item with the given rating key, without starting
+// a transcode
+func (server Server) TranscodeDecision(ratingKey string, opts TranscodeOptions) (TranscodeDecision, error) {
+	container := &transcodeDecisionResp{}
+	if err := server.fetch("GET", "/video/:/transcode/universal/decision", opts.params(ratingKey), container); err != nil {
+		return TranscodeDecision{}, err
+	}
+
+	decision := TranscodeDecision{
+		GeneralCode:    container.GeneralCode,
+		GeneralText:    container.GeneralText,
+		DirectPlayCode: container.DirectPlayCode,
+		DirectPlayText: container.DirectPlayText,
+		TranscodeCode:  container.TranscodeCode,
+		TranscodeText:  container.TranscodeText,
+	}
+	if len(container.Items) > 0 {
+		decision.Item = container.Items[0]
+		decision.Item.Server = server
+	}
+
+	return decision, nil
+}
+
+func (opts TranscodeOptions) protocol() StreamProtocol {
+	if opts.Protocol == "" {
+		return ProtocolHLS
+	}
+	return opts.Protocol
+}
+
+func (opts TranscodeOptions) platform() string {
+	if opts.Platform == "" {
+		return "Generic"
+	}
+	return opts.Platform
+}
+
+func (opts TranscodeOptions) product() string {
+	if opts.Product == "" {
+		return clientIdentifier
+	}
+	return opts.Product
+}
+
+func (opts TranscodeOptions) extension() string {
+	if opts.protocol() == ProtocolDASH {
+		return "mpd"
+	}
+	return "m3u8"
+}
+
+func (opts TranscodeOptions) params(ratingKey string) url.Values {
+	params := url.Values{}
+	params.Set("path", "/library/metadata/"+ratingKey)
+	params.Set("mediaIndex", "0")
+	params.Set("partIndex", "0")
+	params.Set("protocol", string(opts.protocol()))
+	params.Set("fastSeek", "1")
+	params.Set("directPlay", boolParam(!opts.DisableDirectPlay))
+	params.Set("directStream", boolParam(!opts.DisableDirectStream))
+	params.Set("X-Plex-Client-Identifier", clientIdentifier)
+	params.Set("X-Plex-Platform", opts.platform())
+	params.Set("X-Plex-Product", opts.product())
+
+	if opts.MaxVideoBitrate > 0 {
+		params.Set("maxVideoBitrate", strconv.Itoa(opts.MaxVideoBitrate))
+	}
+	if opts.VideoResolution != "" {
+		params.Set("videoResolution", opts.VideoResolution)
+	}
+	if opts.AudioStreamID != 0 {
+		params.Set("audioStreamID", strconv.FormatInt(opts.AudioStreamID, 10))
+	}
+	if opts.SubtitleStreamID != 0 {
+		params.Set("subtitleStreamID", strconv.FormatInt(opts.SubtitleStreamID, 10))
+	}
+	if opts.BurnSubtitles {
+		params.Set("subtitles", "burn")
+	}
+	if opts.Offset > 0 {
+		params.Set("offset", strconv.FormatInt(int64(opts.Offset/time.Second), 10))
+	}
+	if opts.SessionID != "" {
+		params.Set("session", opts.SessionID)
+		params.Set("X-Plex-Session-Identifier", opts.SessionID)
+	}
+	if len(opts.ClientProfileExtra) > 0 {
+		params.Set("X-Plex-Client-Profile-Extra", strings.Join(opts.ClientProfileExtra, "+"))
+	}
+
+	return params
+}
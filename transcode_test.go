@@ -0,0 +1,188 @@
+package plex
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTranscodeURL(t *testing.T) {
+	opts := TranscodeOptions{
+		MaxVideoBitrate:  4000,
+		VideoResolution:  "1280x720",
+		SubtitleStreamID: 10814,
+		BurnSubtitles:    true,
+		Offset:           90 * time.Second,
+		SessionID:        "session1",
+	}
+	result := makeTestServer().TranscodeURL("1751", opts)
+
+	expected := "http://server.com:4040/video/:/transcode/universal/start.m3u8?" +
+		"X-Plex-Client-Identifier=plextrack&X-Plex-Platform=Generic&X-Plex-Product=plextrack&" +
+		"X-Plex-Session-Identifier=session1&X-Plex-Token=authToken&directPlay=1&directStream=1&fastSeek=1&" +
+		"maxVideoBitrate=4000&mediaIndex=0&offset=90&partIndex=0&path=%2Flibrary%2Fmetadata%2F1751&protocol=hls&" +
+		"session=session1&subtitleStreamID=10814&subtitles=burn&videoResolution=1280x720"
+	if result.String() != expected {
+		t.Fatalf("\nExpected: %s\n\nGot: %s", expected, result)
+	}
+}
+
+func TestTranscodeURLDASH(t *testing.T) {
+	opts := TranscodeOptions{
+		Protocol:           ProtocolDASH,
+		DisableDirectPlay:  true,
+		AudioStreamID:      10813,
+		Platform:           "Chrome",
+		Product:            "Plex Web",
+		ClientProfileExtra: []string{"add-transcode-target(type=videoProfile&protocol=dash)", "append-transcode-target-codec(type=videoProfile&audioCodec=aac)"},
+	}
+	result := makeTestServer().TranscodeURL("1751", opts)
+
+	expected := "http://server.com:4040/video/:/transcode/universal/start.mpd?" +
+		"X-Plex-Client-Identifier=plextrack&" +
+		"X-Plex-Client-Profile-Extra=add-transcode-target%28type%3DvideoProfile%26protocol%3Ddash%29%2B" +
+		"append-transcode-target-codec%28type%3DvideoProfile%26audioCodec%3Daac%29&" +
+		"X-Plex-Platform=Chrome&X-Plex-Product=Plex+Web&X-Plex-Token=authToken&" +
+		"audioStreamID=10813&directPlay=0&directStream=1&fastSeek=1&mediaIndex=0&partIndex=0&" +
+		"path=%2Flibrary%2Fmetadata%2F1751&protocol=dash"
+	if result.String() != expected {
+		t.Fatalf("\nExpected: %s\n\nGot: %s", expected, result)
+	}
+}
+
+func TestTranscodeDecisionSuccess(t *testing.T) {
+	resp := `<?xml version="1.0" encoding="UTF-8"?>
+	<MediaContainer size="1" directPlayDecisionCode="3000" directPlayDecisionText="App cannot direct play this item. Direct play is disabled." generalDecisionCode="1001" generalDecisionText="Direct play not available; Conversion OK." transcodeDecisionCode="1001" transcodeDecisionText="Direct play not available; Conversion OK.">
+	  <Video ratingKey="1751" key="/library/metadata/1751" type="episode" title="Episode 21">
+	    <Media height="720" videoResolution="720" width="1280" audioCodec="aac" videoCodec="h264">
+	      <Part id="2147" decision="transcode">
+	        <Stream id="10812" streamType="1" codec="h264" decision="copy" />
+	        <Stream id="10813" streamType="2" codec="aac" decision="transcode" selected="1" />
+	      </Part>
+	    </Media>
+	  </Video>
+	</MediaContainer>`
+
+	expectedURL := "http://server.com:4040/video/:/transcode/universal/decision?" +
+		"X-Plex-Client-Identifier=plextrack&X-Plex-Platform=Generic&X-Plex-Product=plextrack&" +
+		"directPlay=0&directStream=1&fastSeek=1&mediaIndex=0&partIndex=0&path=%2Flibrary%2Fmetadata%2F1751&protocol=hls"
+	client = makeFakeClient(t, http.StatusOK, resp, makeServerRequest(t, "GET", expectedURL))
+
+	server := makeTestServer()
+	result, err := server.TranscodeDecision("1751", TranscodeOptions{DisableDirectPlay: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := TranscodeDecision{
+		GeneralCode:    1001,
+		GeneralText:    "Direct play not available; Conversion OK.",
+		DirectPlayCode: 3000,
+		DirectPlayText: "App cannot direct play this item. Direct play is disabled.",
+		TranscodeCode:  1001,
+		TranscodeText:  "Direct play not available; Conversion OK.",
+		Item: Video{
+			RatingKey: "1751",
+			Key:       "/library/metadata/1751",
+			Type:      "episode",
+			Title:     "Episode 21",
+			Media: Media{
+				AudioCodec:      "aac",
+				VideoCodec:      "h264",
+				HeightPx:        720,
+				WidthPx:         1280,
+				VideoResolution: "720",
+				Parts: []Part{
+					Part{
+						ID:       2147,
+						Decision: "transcode",
+						Streams: []Stream{
+							Stream{ID: 10812, StreamType: StreamTypeVideo, Codec: "h264", Decision: "copy"},
+							Stream{ID: 10813, StreamType: StreamTypeAudio, Codec: "aac", Decision: "transcode", Selected: true},
+						},
+					},
+				},
+			},
+			Server: server,
+		},
+	}
+
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("\nExpected: %+v\n\nGot: %+v", expected, result)
+	}
+	if result.DirectPlay() {
+		t.Fatal("Decision should not be direct play")
+	}
+}
+
+func TestTranscodeDecisionJSONSuccess(t *testing.T) {
+	resp := `{"MediaContainer": {"size": 1, "directPlayDecisionCode": 1000, "directPlayDecisionText": "Direct play OK.", "generalDecisionCode": 1000, "generalDecisionText": "Direct play OK.", "transcodeDecisionCode": 1000, "transcodeDecisionText": "Direct play OK.", "Metadata": [
+	  {"ratingKey": "1751", "key": "/library/metadata/1751", "type": "episode", "title": "Episode 21", "Media": [
+	    {"height": 720, "videoResolution": "720", "width": 1280, "audioCodec": "aac", "videoCodec": "h264", "Part": [
+	      {"id": 2147, "decision": "directplay", "Stream": [
+	        {"id": 10812, "streamType": 1, "codec": "h264", "decision": "copy"}
+	      ]}
+	    ]}
+	  ]}
+	]}}`
+
+	expectedURL := "http://server.com:4040/video/:/transcode/universal/decision?" +
+		"X-Plex-Client-Identifier=plextrack&X-Plex-Platform=Generic&X-Plex-Product=plextrack&" +
+		"directPlay=1&directStream=1&fastSeek=1&mediaIndex=0&partIndex=0&path=%2Flibrary%2Fmetadata%2F1751&protocol=hls"
+	client = makeFakeClient(t, http.StatusOK, resp, makeJSONServerRequest(t, "GET", expectedURL))
+
+	server := makeJSONTestServer()
+	result, err := server.TranscodeDecision("1751", TranscodeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := TranscodeDecision{
+		GeneralCode:    1000,
+		GeneralText:    "Direct play OK.",
+		DirectPlayCode: 1000,
+		DirectPlayText: "Direct play OK.",
+		TranscodeCode:  1000,
+		TranscodeText:  "Direct play OK.",
+		Item: Video{
+			RatingKey: "1751",
+			Key:       "/library/metadata/1751",
+			Type:      "episode",
+			Title:     "Episode 21",
+			Media: Media{
+				AudioCodec:      "aac",
+				VideoCodec:      "h264",
+				HeightPx:        720,
+				WidthPx:         1280,
+				VideoResolution: "720",
+				Parts: []Part{
+					Part{
+						ID:       2147,
+						Decision: "directplay",
+						Streams:  []Stream{Stream{ID: 10812, StreamType: StreamTypeVideo, Codec: "h264", Decision: "copy"}},
+					},
+				},
+			},
+			Server: server,
+		},
+	}
+
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("\nExpected: %+v\n\nGot: %+v", expected, result)
+	}
+	if !result.DirectPlay() {
+		t.Fatal("Decision should be direct play")
+	}
+}
+
+func TestTranscodeDecisionFail(t *testing.T) {
+	expectedURL := "http://server.com:4040/video/:/transcode/universal/decision?" +
+		"X-Plex-Client-Identifier=plextrack&X-Plex-Platform=Generic&X-Plex-Product=plextrack&" +
+		"directPlay=1&directStream=1&fastSeek=1&mediaIndex=0&partIndex=0&path=%2Flibrary%2Fmetadata%2F1751&protocol=hls"
+	client = makeFakeClient(t, http.StatusBadRequest, "", makeServerRequest(t, "GET", expectedURL))
+
+	if _, err := makeTestServer().TranscodeDecision("1751", TranscodeOptions{}); err == nil {
+		t.Fatal("TranscodeDecision returned success when it received bad status code")
+	}
+}
@@ -1,18 +1,20 @@
 package plex
 
 type User struct {
-	Email        string       `xml:"email,attr"`
-	ID           int64        `xml:"id,attr"`
-	Thumb        HTTPURL      `xml:"thumb,attr"`
-	Username     string       `xml:"username,attr"`
-	Title        string       `xml:"title,attr"`
-	Locale       string       `xml:"locale,attr"`
-	AuthToken    string       `xml:"authenticationToken,attr"`
-	QueueEmail   string       `xml:"queueEmail,attr"`
-	Subscription Subscription `xml:"subscription"`
+	Email        string       `xml:"email,attr" json:"email"`
+	ID           int64        `xml:"id,attr" json:"id,string"`
+	Thumb        HTTPURL      `xml:"thumb,attr" json:"thumb"`
+	Username     string       `xml:"username,attr" json:"username"`
+	Title        string       `xml:"title,attr" json:"title"`
+	Locale       string       `xml:"locale,attr" json:"locale"`
+	AuthToken    string       `xml:"authenticationToken,attr" json:"authenticationToken"`
+	QueueEmail   string       `xml:"queueEmail,attr" json:"queueEmail"`
+	Subscription Subscription `xml:"subscription" json:"subscription"`
+	// Retry is the policy for requests to plex.tv, and for the user's servers returned by GetServers
+	Retry RetryPolicy `xml:"-" json:"-"`
 }
 
 type Subscription struct {
-	Active IntAsBool `xml:"active,attr"`
-	Plan   string    `xml:"plan,attr"`
+	Active IntAsBool `xml:"active,attr" json:"active"`
+	Plan   string    `xml:"plan,attr" json:"plan"`
 }
@@ -1,56 +1,147 @@
 package plex
 
+import "encoding/json"
+
 type Video struct {
-	AddedAt          UnixTime       `xml:"addedAt,attr"`
-	Art              URLPath        `xml:"art,attr"`
-	ContentRating    string         `xml:"contentRating,attr"`
-	Duration         MillisDuration `xml:"duration,attr"`
-	GrandparentArt   URLPath        `xml:"grandparentArt,attr"`
-	GrandparentTheme URLPath        `xml:"grandparentTheme,attr"`
-	GrandparentThumb URLPath        `xml:"grandparentThumb,attr"`
-	GrandparentTitle string         `xml:"grandparentTitle,attr"`
-	GUID             string         `xml:"guid,attr"`
-	ParentThumb      URLPath        `xml:"parentThumb,attr"`
-	Thumb            URLPath        `xml:"thumb,attr"`
-	Title            string         `xml:"title,attr"`
-	UpdatedAt        UnixTime       `xml:"updatedAt,attr"`
+	AddedAt          UnixTime       `xml:"addedAt,attr" json:"addedAt"`
+	Art              URLPath        `xml:"art,attr" json:"art"`
+	ContentRating    string         `xml:"contentRating,attr" json:"contentRating"`
+	Duration         MillisDuration `xml:"duration,attr" json:"duration"`
+	GrandparentArt   URLPath        `xml:"grandparentArt,attr" json:"grandparentArt"`
+	GrandparentTheme URLPath        `xml:"grandparentTheme,attr" json:"grandparentTheme"`
+	GrandparentThumb URLPath        `xml:"grandparentThumb,attr" json:"grandparentThumb"`
+	GrandparentTitle string         `xml:"grandparentTitle,attr" json:"grandparentTitle"`
+	GUID             string         `xml:"guid,attr" json:"guid"`
+	Key              string         `xml:"key,attr" json:"key"`
+	LibrarySectionID ID             `xml:"librarySectionID,attr" json:"librarySectionID"`
+	ParentThumb      URLPath        `xml:"parentThumb,attr" json:"parentThumb"`
+	RatingKey        string         `xml:"ratingKey,attr" json:"ratingKey"`
+	Summary          string         `xml:"summary,attr" json:"summary"`
+	Thumb            URLPath        `xml:"thumb,attr" json:"thumb"`
+	Title            string         `xml:"title,attr" json:"title"`
+	Type             string         `xml:"type,attr" json:"type"`
+	UpdatedAt        UnixTime       `xml:"updatedAt,attr" json:"updatedAt"`
 	Media            Media
 	User             User
 	Player           Player
 	TranscodeSession TranscodeSession
+	Session          Session
+	Server           Server `xml:"-" json:"-"`
 }
 
 type Media struct {
-	AspectRatio    float32 `xml:"aspectRatio,attr"`
-	AudioChannels  int     `xml:"audioChannels,attr"`
-	AudioCodec     string  `xml:"audioCodec,attr"`
-	VideoCodec     string  `xml:"videoCodec,attr"`
-	VideoFrameRate string  `xml:"videoFrameRate,attr"`
-	HeightPx       int     `xml:"videoResolution,attr"`
-	WidthPx        int     `xml:"width,attr"`
+	AspectRatio     float32 `xml:"aspectRatio,attr" json:"aspectRatio"`
+	AudioChannels   int     `xml:"audioChannels,attr" json:"audioChannels"`
+	AudioCodec      string  `xml:"audioCodec,attr" json:"audioCodec"`
+	Bitrate         int     `xml:"bitrate,attr" json:"bitrate"`
+	Container       string  `xml:"container,attr" json:"container"`
+	VideoCodec      string  `xml:"videoCodec,attr" json:"videoCodec"`
+	VideoFrameRate  string  `xml:"videoFrameRate,attr" json:"videoFrameRate"`
+	HeightPx        int     `xml:"height,attr" json:"height"`
+	WidthPx         int     `xml:"width,attr" json:"width"`
+	VideoResolution string  `xml:"videoResolution,attr" json:"videoResolution"`
+	Parts           []Part  `xml:"Part" json:"Part"`
+}
+
+// Part is one of the files that make up a piece of media
+type Part struct {
+	ID        int64          `xml:"id,attr" json:"id"`
+	Key       URLPath        `xml:"key,attr" json:"key"`
+	File      string         `xml:"file,attr" json:"file"`
+	Size      int64          `xml:"size,attr" json:"size"`
+	Container string         `xml:"container,attr" json:"container"`
+	Duration  MillisDuration `xml:"duration,attr" json:"duration"`
+	Decision  string         `xml:"decision,attr" json:"decision"`
+	Streams   []Stream       `xml:"Stream" json:"Stream"`
+}
+
+type StreamType int
+
+const (
+	StreamTypeVideo    StreamType = 1
+	StreamTypeAudio    StreamType = 2
+	StreamTypeSubtitle StreamType = 3
+)
+
+// Stream is a video, audio or subtitle track of a part. Decision is only set on the streams of a transcode
+// decision or session, and says whether the stream is copied, transcoded or burned in.
+type Stream struct {
+	ID           int64      `xml:"id,attr" json:"id"`
+	StreamType   StreamType `xml:"streamType,attr" json:"streamType"`
+	Index        int        `xml:"index,attr" json:"index"`
+	Codec        string     `xml:"codec,attr" json:"codec"`
+	Bitrate      int        `xml:"bitrate,attr" json:"bitrate"`
+	Language     string     `xml:"language,attr" json:"language"`
+	LanguageCode string     `xml:"languageCode,attr" json:"languageCode"`
+	Width        int        `xml:"width,attr" json:"width"`
+	Height       int        `xml:"height,attr" json:"height"`
+	Channels     int        `xml:"channels,attr" json:"channels"`
+	Selected     IntAsBool  `xml:"selected,attr" json:"selected"`
+	Decision     string     `xml:"decision,attr" json:"decision"`
 }
 
 type Player struct {
-	MachineIdentifier string `xml:"machineIdentifier,attr"`
-	Platform          string `xml:"platform,attr"`
-	Product           string `xml:"product,attr"`
-	State             string `xml:"state,attr"`
-	Title             string `xml:"title,attr"`
+	MachineIdentifier string `xml:"machineIdentifier,attr" json:"machineIdentifier"`
+	Platform          string `xml:"platform,attr" json:"platform"`
+	Product           string `xml:"product,attr" json:"product"`
+	State             string `xml:"state,attr" json:"state"`
+	Title             string `xml:"title,attr" json:"title"`
+}
+
+// Session is how a client is connected to the server while playing an item
+type Session struct {
+	ID string `xml:"id,attr" json:"id"`
+	// Bandwidth is in kbps
+	Bandwidth int `xml:"bandwidth,attr" json:"bandwidth"`
+	// Location is lan or wan
+	Location string `xml:"location,attr" json:"location"`
 }
 
 type TranscodeSession struct {
-	Key           string         `xml:"key,attr"`
-	Throttled     IntAsBool      `xml:"throttled,attr"`
-	Progress      float64        `xml:"progress,attr"`
-	Speed         float64        `xml:"speed,attr"`
-	Duration      MillisDuration `xml:"duration,attr"`
-	VideoDecision string         `xml:"videoDecision,attr"`
-	AudioDecision string         `xml:"audioDecision,attr"`
-	Protocol      string         `xml:"protocol,attr"`
-	Container     string         `xml:"container,attr"`
-	VideoCodec    string         `xml:"videoCodec,attr"`
-	AudioCodec    string         `xml:"audioCodec,attr"`
-	AudioChannels int            `xml:"audioChannels,attr"`
-	Width         int            `xml:"width,attr"`
-	Height        int            `xml:"height,attr"`
+	Key           string         `xml:"key,attr" json:"key"`
+	Throttled     IntAsBool      `xml:"throttled,attr" json:"throttled"`
+	Progress      float64        `xml:"progress,attr" json:"progress"`
+	Speed         float64        `xml:"speed,attr" json:"speed"`
+	Duration      MillisDuration `xml:"duration,attr" json:"duration"`
+	VideoDecision string         `xml:"videoDecision,attr" json:"videoDecision"`
+	AudioDecision string         `xml:"audioDecision,attr" json:"audioDecision"`
+	Protocol      string         `xml:"protocol,attr" json:"protocol"`
+	Container     string         `xml:"container,attr" json:"container"`
+	VideoCodec    string         `xml:"videoCodec,attr" json:"videoCodec"`
+	AudioCodec    string         `xml:"audioCodec,attr" json:"audioCodec"`
+	AudioChannels int            `xml:"audioChannels,attr" json:"audioChannels"`
+	Width         int            `xml:"width,attr" json:"width"`
+	Height        int            `xml:"height,attr" json:"height"`
+	// The source codecs and subtitle decision are only sent by newer servers
+	SourceVideoCodec     string    `xml:"sourceVideoCodec,attr" json:"sourceVideoCodec"`
+	SourceAudioCodec     string    `xml:"sourceAudioCodec,attr" json:"sourceAudioCodec"`
+	SubtitleDecision     string    `xml:"subtitleDecision,attr" json:"subtitleDecision"`
+	TranscodeHwRequested IntAsBool `xml:"transcodeHwRequested,attr" json:"transcodeHwRequested"`
+}
+
+// UnmarshalJSON decodes a video from the JSON format, where the server sends Media as a list
+func (v *Video) UnmarshalJSON(data []byte) error {
+	type video Video
+	aux := struct {
+		*video
+		Media []Media `json:"Media"`
+	}{video: (*video)(v)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if len(aux.Media) > 0 {
+		v.Media = aux.Media[0]
+	}
+	return nil
+}
+
+// MarshalJSON encodes Media as a list to match UnmarshalJSON
+func (v Video) MarshalJSON() ([]byte, error) {
+	type video Video
+	return json.Marshal(struct {
+		video
+		Media []Media `json:"Media"`
+	}{video(v), []Media{v.Media}})
 }
@@ -1,6 +1,7 @@
 package plex
 
 import (
+	"encoding/json"
 	"encoding/xml"
 	"net/url"
 	"strconv"
@@ -8,37 +9,162 @@ import (
 	"time"
 )
 
+// The custom types below are decoded from the attributes of XML responses and the values of JSON responses.
+// In JSON, the server sends numbers and booleans unquoted, but some versions quote them.
+// They marshal back to the same representation the server uses, so decoded objects can be stored and re-served.
+// Nil slices and zero URLs and times are marshaled as a missing attribute in XML and null in JSON. MillisDuration
+// and IntAsBool always marshal a value, so their zero values are sent as 0 and false.
+
+// jsonValue returns the contents of a JSON string, or the literal text of any other JSON value
+func jsonValue(data []byte) (string, error) {
+	if len(data) > 0 && data[0] == '"' {
+		var value string
+		err := json.Unmarshal(data, &value)
+		return value, err
+	}
+	return string(data), nil
+}
+
+// omittedAttr is the attribute encoding/xml leaves out of the output
+var omittedAttr = xml.Attr{}
+
+// CommaSeperatedSlice is a list sent as its elements joined by commas. An empty slice is sent as an empty string.
+// Elements containing commas don't round trip, they are split apart when decoded, and neither does a single empty
+// element, which decodes as an empty slice.
 type CommaSeperatedSlice []string
 
+func (s CommaSeperatedSlice) String() string {
+	return strings.Join(s, ",")
+}
+
 func (s *CommaSeperatedSlice) UnmarshalXMLAttr(attr xml.Attr) error {
+	if attr.Value == "" {
+		*s = CommaSeperatedSlice{}
+		return nil
+	}
 	*s = CommaSeperatedSlice(strings.Split(attr.Value, ","))
 	return nil
 }
 
+func (s CommaSeperatedSlice) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	if s == nil {
+		return omittedAttr, nil
+	}
+	return xml.Attr{Name: name, Value: s.String()}, nil
+}
+
+func (s *CommaSeperatedSlice) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	value, err := jsonValue(data)
+	if err != nil {
+		return err
+	}
+	return s.UnmarshalXMLAttr(xml.Attr{Value: value})
+}
+
+func (s CommaSeperatedSlice) MarshalJSON() ([]byte, error) {
+	if s == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(s.String())
+}
+
 type HTTPURL struct {
 	url.URL
 }
 
+func (u HTTPURL) String() string {
+	return u.URL.String()
+}
+
 func (u *HTTPURL) UnmarshalXMLAttr(attr xml.Attr) error {
-	if !strings.HasPrefix(attr.Value, "http://") {
+	if attr.Value == "" {
+		*u = HTTPURL{}
+		return nil
+	}
+
+	if !strings.HasPrefix(attr.Value, "http://") && !strings.HasPrefix(attr.Value, "https://") {
 		attr.Value = "http://" + attr.Value
 	}
 
 	url, err := url.Parse(attr.Value)
+	if err != nil {
+		return err
+	}
 	*u = HTTPURL{*url}
-	return err
+	return nil
+}
+
+func (u HTTPURL) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	if u == (HTTPURL{}) {
+		return omittedAttr, nil
+	}
+	return xml.Attr{Name: name, Value: u.String()}, nil
+}
+
+func (u *HTTPURL) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	value, err := jsonValue(data)
+	if err != nil {
+		return err
+	}
+	return u.UnmarshalXMLAttr(xml.Attr{Value: value})
+}
+
+func (u HTTPURL) MarshalJSON() ([]byte, error) {
+	if u == (HTTPURL{}) {
+		return []byte("null"), nil
+	}
+	return json.Marshal(u.String())
 }
 
 type URLPath struct {
 	url.URL
 }
 
+func (p URLPath) String() string {
+	return p.URL.String()
+}
+
 func (p *URLPath) UnmarshalXMLAttr(attr xml.Attr) error {
 	url, err := url.Parse(attr.Value)
+	if err != nil {
+		return err
+	}
 	*p = URLPath{*url}
-	return err
+	return nil
+}
+
+func (p URLPath) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	if p == (URLPath{}) {
+		return omittedAttr, nil
+	}
+	return xml.Attr{Name: name, Value: p.String()}, nil
+}
+
+func (p *URLPath) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	value, err := jsonValue(data)
+	if err != nil {
+		return err
+	}
+	return p.UnmarshalXMLAttr(xml.Attr{Value: value})
+}
+
+func (p URLPath) MarshalJSON() ([]byte, error) {
+	if p == (URLPath{}) {
+		return []byte("null"), nil
+	}
+	return json.Marshal(p.String())
 }
 
+// UnixTime is a time sent as seconds since the epoch. It prints like a time.Time.
 type UnixTime struct {
 	time.Time
 }
@@ -53,8 +179,42 @@ func (t *UnixTime) UnmarshalXMLAttr(attr xml.Attr) error {
 	return nil
 }
 
+func (t UnixTime) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	if t.IsZero() {
+		return omittedAttr, nil
+	}
+	return xml.Attr{Name: name, Value: strconv.FormatInt(t.Unix(), 10)}, nil
+}
+
+func (t *UnixTime) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	value, err := jsonValue(data)
+	if err != nil {
+		return err
+	}
+	return t.UnmarshalXMLAttr(xml.Attr{Value: value})
+}
+
+func (t UnixTime) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.FormatInt(t.Unix(), 10)), nil
+}
+
+// MillisDuration is a duration sent as a number of milliseconds
 type MillisDuration time.Duration
 
+func (dur MillisDuration) String() string {
+	return time.Duration(dur).String()
+}
+
+func (dur MillisDuration) millis() string {
+	return strconv.FormatInt(int64(time.Duration(dur)/time.Millisecond), 10)
+}
+
 func (dur *MillisDuration) UnmarshalXMLAttr(attr xml.Attr) error {
 	millis, err := strconv.ParseInt(attr.Value, 10, 64)
 	if err != nil {
@@ -65,9 +225,78 @@ func (dur *MillisDuration) UnmarshalXMLAttr(attr xml.Attr) error {
 	return nil
 }
 
+func (dur MillisDuration) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return xml.Attr{Name: name, Value: dur.millis()}, nil
+}
+
+func (dur *MillisDuration) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	value, err := jsonValue(data)
+	if err != nil {
+		return err
+	}
+	return dur.UnmarshalXMLAttr(xml.Attr{Value: value})
+}
+
+func (dur MillisDuration) MarshalJSON() ([]byte, error) {
+	return []byte(dur.millis()), nil
+}
+
+// IntAsBool is a boolean sent as 0 or 1
 type IntAsBool bool
 
+func (v IntAsBool) String() string {
+	return strconv.FormatBool(bool(v))
+}
+
 func (v *IntAsBool) UnmarshalXMLAttr(attr xml.Attr) error {
 	*v = attr.Value == "1"
 	return nil
 }
+
+func (v IntAsBool) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	value := "0"
+	if v {
+		value = "1"
+	}
+	return xml.Attr{Name: name, Value: value}, nil
+}
+
+// UnmarshalJSON accepts both the 0 and 1 used in XML and true and false
+func (v *IntAsBool) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	value, err := jsonValue(data)
+	if err != nil {
+		return err
+	}
+	*v = value == "1" || value == "true"
+	return nil
+}
+
+func (v IntAsBool) MarshalJSON() ([]byte, error) {
+	return json.Marshal(bool(v))
+}
+
+// ID is an identifier the server sends as a JSON string in some responses and a JSON number in others, such as
+// the library section an item belongs to. It is kept as the string XML responses use.
+type ID string
+
+func (id ID) String() string {
+	return string(id)
+}
+
+func (id *ID) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	value, err := jsonValue(data)
+	if err != nil {
+		return err
+	}
+	*id = ID(value)
+	return nil
+}
@@ -0,0 +1,232 @@
+package plex
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+	"time"
+	"unicode"
+)
+
+func TestUnmarshalJSONTypes(t *testing.T) {
+	type values struct {
+		Slice    CommaSeperatedSlice
+		HTTPURL  HTTPURL
+		URLPath  URLPath
+		Time     UnixTime
+		Duration MillisDuration
+		Bool     IntAsBool
+		ID       ID
+	}
+
+	expected := values{
+		Slice:    []string{"server", "player"},
+		HTTPURL:  HTTPURL{url.URL{Scheme: "http", Host: "thumb.com"}},
+		URLPath:  URLPath{url.URL{Path: "/library/metadata/1/thumb"}},
+		Time:     UnixTime{time.Unix(1430373171, 0)},
+		Duration: MillisDuration(1500 * time.Millisecond),
+		Bool:     true,
+		ID:       "1",
+	}
+
+	inputs := []string{
+		`{"Slice": "server,player", "HTTPURL": "thumb.com", "URLPath": "/library/metadata/1/thumb",
+			"Time": 1430373171, "Duration": 1500, "Bool": true, "ID": 1}`,
+		`{"Slice": "server,player", "HTTPURL": "http://thumb.com", "URLPath": "/library/metadata/1/thumb",
+			"Time": "1430373171", "Duration": "1500", "Bool": 1, "ID": "1"}`,
+		`{"Slice": "server,player", "HTTPURL": "thumb.com", "URLPath": "/library/metadata/1/thumb",
+			"Time": 1430373171, "Duration": 1500, "Bool": "1", "ID": "1"}`,
+	}
+
+	for _, input := range inputs {
+		result := values{}
+		if err := json.Unmarshal([]byte(input), &result); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(expected, result) {
+			t.Fatalf("\nInput: %s\nExpected: %+v\nGot: %+v", input, expected, result)
+		}
+	}
+}
+
+func TestUnmarshalJSONTypesNull(t *testing.T) {
+	result := struct {
+		Time UnixTime
+		Bool IntAsBool
+	}{}
+	if err := json.Unmarshal([]byte(`{"Time": null, "Bool": null}`), &result); err != nil {
+		t.Fatal(err)
+	}
+	if !result.Time.IsZero() || bool(result.Bool) {
+		t.Fatalf("Expected zero values, got %+v", result)
+	}
+}
+
+func TestUnmarshalJSONTypesFail(t *testing.T) {
+	result := struct{ Time UnixTime }{}
+	if err := json.Unmarshal([]byte(`{"Time": "yesterday"}`), &result); err == nil {
+		t.Fatal("Should err when a time is not a number")
+	}
+}
+
+// alphanumeric strips s down to characters that survive in any attribute or URL unescaped
+func alphanumeric(s string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// sliceElement strips s of the commas CommaSeperatedSlice can't carry and the characters XML can't
+func sliceElement(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ',' || !unicode.IsPrint(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+type roundTripAttrs struct {
+	XMLName  xml.Name            `xml:"Attrs"`
+	Slice    CommaSeperatedSlice `xml:"slice,attr" json:"slice"`
+	HTTPURL  HTTPURL             `xml:"httpURL,attr" json:"httpURL"`
+	URLPath  URLPath             `xml:"urlPath,attr" json:"urlPath"`
+	Time     UnixTime            `xml:"time,attr" json:"time"`
+	Duration MillisDuration      `xml:"duration,attr" json:"duration"`
+	Bool     IntAsBool           `xml:"bool,attr" json:"bool"`
+}
+
+// roundTrip checks that v marshals and unmarshals back to itself in both XML and JSON
+func roundTrip(t *testing.T, v interface{}, empty func() interface{}) bool {
+	xmlContent, err := xml.Marshal(v)
+	if err != nil {
+		t.Error(err)
+		return false
+	}
+	fromXML := empty()
+	if err := xml.Unmarshal(xmlContent, fromXML); err != nil {
+		t.Error(err)
+		return false
+	}
+
+	jsonContent, err := json.Marshal(v)
+	if err != nil {
+		t.Error(err)
+		return false
+	}
+	fromJSON := empty()
+	if err := json.Unmarshal(jsonContent, fromJSON); err != nil {
+		t.Error(err)
+		return false
+	}
+
+	expected := reflect.New(reflect.TypeOf(v)).Interface()
+	reflect.ValueOf(expected).Elem().Set(reflect.ValueOf(v))
+	if !reflect.DeepEqual(expected, fromXML) {
+		t.Errorf("XML round trip failed\nExpected: %+v\nXML: %s\nGot: %+v", v, xmlContent, fromXML)
+		return false
+	}
+	if !reflect.DeepEqual(expected, fromJSON) {
+		t.Errorf("JSON round trip failed\nExpected: %+v\nJSON: %s\nGot: %+v", v, jsonContent, fromJSON)
+		return false
+	}
+	return true
+}
+
+func TestAttrTypesRoundTrip(t *testing.T) {
+	check := func(parts []string, scheme bool, host, path string, sec int64, millis int32, b bool) bool {
+		attrs := roundTripAttrs{
+			XMLName:  xml.Name{Local: "Attrs"},
+			Duration: MillisDuration(time.Duration(millis) * time.Millisecond),
+			Bool:     IntAsBool(b),
+		}
+
+		if parts != nil {
+			attrs.Slice = CommaSeperatedSlice{}
+		}
+		for _, part := range parts {
+			attrs.Slice = append(attrs.Slice, sliceElement(part))
+		}
+		// A single empty element is sent the same way as an empty slice
+		if len(attrs.Slice) == 1 && attrs.Slice[0] == "" {
+			attrs.Slice = CommaSeperatedSlice{}
+		}
+		if host = alphanumeric(host); host != "" {
+			attrs.HTTPURL = HTTPURL{url.URL{Scheme: "http", Host: host + ".com", Path: "/" + alphanumeric(path)}}
+			if scheme {
+				attrs.HTTPURL.Scheme = "https"
+			}
+		}
+		if path = alphanumeric(path); path != "" {
+			attrs.URLPath = URLPath{url.URL{Path: "/library/" + path}}
+		}
+		if sec != 0 {
+			attrs.Time = UnixTime{time.Unix(sec, 0)}
+		}
+
+		return roundTrip(t, attrs, func() interface{} { return &roundTripAttrs{} })
+	}
+
+	if err := quick.Check(check, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEmptySliceRoundTrip(t *testing.T) {
+	for _, slice := range []CommaSeperatedSlice{nil, CommaSeperatedSlice{}} {
+		attrs := roundTripAttrs{XMLName: xml.Name{Local: "Attrs"}, Slice: slice}
+		roundTrip(t, attrs, func() interface{} { return &roundTripAttrs{} })
+	}
+}
+
+func TestVideoRoundTrip(t *testing.T) {
+	for _, video := range makeExpectedActivity() {
+		roundTrip(t, video, func() interface{} { return &Video{} })
+	}
+}
+
+func TestUserRoundTrip(t *testing.T) {
+	check := func(email, title string, id int64, active bool) bool {
+		user := User{
+			Email:        alphanumeric(email) + "@address.com",
+			ID:           id,
+			Thumb:        HTTPURL{url.URL{Scheme: "https", Host: "thumb.com", Path: "/" + alphanumeric(title)}},
+			Title:        alphanumeric(title),
+			AuthToken:    "authtoken",
+			Subscription: Subscription{Active: IntAsBool(active), Plan: "lifetime"},
+		}
+		return roundTrip(t, user, func() interface{} { return &User{} })
+	}
+
+	if err := quick.Check(check, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAttrTypesString(t *testing.T) {
+	tests := []struct {
+		value    fmt.Stringer
+		expected string
+	}{
+		{CommaSeperatedSlice{"server", "player"}, "server,player"},
+		{HTTPURL{url.URL{Scheme: "https", Host: "plex.tv", Path: "/users"}}, "https://plex.tv/users"},
+		{URLPath{url.URL{Path: "/library/metadata/1/thumb"}}, "/library/metadata/1/thumb"},
+		{MillisDuration(1500 * time.Millisecond), "1.5s"},
+		{IntAsBool(true), "true"},
+		{ID("1"), "1"},
+	}
+
+	for _, test := range tests {
+		if result := test.value.String(); result != test.expected {
+			t.Errorf("Expected %s, got %s", test.expected, result)
+		}
+	}
+}